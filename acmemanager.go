@@ -0,0 +1,466 @@
+/*
+	Digivance MVC Application Framework
+	ACME Certificate Manager
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the ACME (RFC 8555) certificate manager. This manager registers an account with
+	the configured certificate authority, answers HTTP-01 challenges from the plain http listener,
+	stores the issued certificates on disk and renews them as they approach expiration.
+*/
+
+package mvcapp
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// acmeChallengePrefix is the url path that ACME certificate authorities request HTTP-01
+// challenge responses from
+const acmeChallengePrefix = "/.well-known/acme-challenge/"
+
+// ACMEManager is used to obtain, store and renew TLS certificates from an ACME (RFC 8555)
+// certificate authority such as Let's Encrypt
+type ACMEManager struct {
+	// DirectoryURL is the ACME directory endpoint of the certificate authority. Can be pointed
+	// at a local stand in (such as Pebble) for testing
+	DirectoryURL string
+
+	// DirectoryCAFile is an optional PEM file of CA certificates to trust when connecting to the
+	// directory (E.g. pebble.minica.pem when testing against Pebble)
+	DirectoryCAFile string
+
+	// Email is the contact address registered with the ACME account
+	Email string
+
+	// Domains is the list of domain names that the certificate is requested for
+	Domains []string
+
+	// CertPath is the folder where the account key and issued certificate are stored
+	CertPath string
+
+	// RenewBefore is the duration before expiration that the certificate will be renewed
+	RenewBefore time.Duration
+
+	// Timeout is the maximum duration allowed for a single registration or certificate order
+	Timeout time.Duration
+
+	// Client is the underlying ACME protocol client, constructed on first use
+	Client *acme.Client
+
+	// clientMutex protects the construction of the Client
+	clientMutex sync.Mutex
+
+	// mutex protects the challenge tokens and certificate members below
+	mutex sync.RWMutex
+
+	// tokens maps pending HTTP-01 challenge tokens to their key authorizations
+	tokens map[string]string
+
+	// certificate is the currently loaded TLS certificate
+	certificate *tls.Certificate
+}
+
+// NewACMEManager returns a new ACME Manager pointed at the Let's Encrypt directory
+func NewACMEManager() *ACMEManager {
+	return &ACMEManager{
+		DirectoryURL: acme.LetsEncryptURL,
+		Domains:      []string{},
+		CertPath:     "./certs",
+		RenewBefore:  30 * 24 * time.Hour,
+		Timeout:      5 * time.Minute,
+		tokens:       make(map[string]string, 0),
+	}
+}
+
+// NewACMEManagerFromConfig returns a new ACME Manager populated from the provided configuration
+// manager object. If no ACMEDomains are configured, the DomainName is used
+func NewACMEManagerFromConfig(config *ConfigurationManager) *ACMEManager {
+	rtn := NewACMEManager()
+	rtn.DirectoryURL = config.ACMEDirectoryURL
+	rtn.DirectoryCAFile = config.ACMEDirectoryCAFile
+	rtn.Email = config.ACMEEmail
+	rtn.CertPath = config.ACMECertPath
+	rtn.RenewBefore = time.Duration(config.ACMERenewDays) * 24 * time.Hour
+
+	if len(config.ACMEDomains) > 0 {
+		rtn.Domains = append(rtn.Domains, config.ACMEDomains...)
+	} else if config.DomainName != "" {
+		rtn.Domains = append(rtn.Domains, config.DomainName)
+	}
+
+	return rtn
+}
+
+// certFolder returns the full path of the CertPath folder
+func (manager *ACMEManager) certFolder() string {
+	path := manager.CertPath
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "./") {
+		path = GetApplicationPath() + path[1:]
+	}
+
+	return path
+}
+
+// AccountKeyFilename returns the full path and filename of the ACME account key
+func (manager *ACMEManager) AccountKeyFilename() string {
+	return fmt.Sprintf("%s/account.key", manager.certFolder())
+}
+
+// CertFilename returns the full path and filename of the issued certificate chain
+func (manager *ACMEManager) CertFilename() string {
+	return fmt.Sprintf("%s/%s.crt", manager.certFolder(), manager.primaryDomain())
+}
+
+// KeyFilename returns the full path and filename of the issued certificate private key
+func (manager *ACMEManager) KeyFilename() string {
+	return fmt.Sprintf("%s/%s.key", manager.certFolder(), manager.primaryDomain())
+}
+
+// primaryDomain returns the first domain name, used to name the certificate files
+func (manager *ACMEManager) primaryDomain() string {
+	if len(manager.Domains) <= 0 {
+		return "default"
+	}
+
+	return manager.Domains[0]
+}
+
+// loadOrCreateKey reads the PEM encoded EC private key from filename, generating and saving
+// a new key if the file does not exist
+func loadOrCreateKey(filename string) (*ecdsa.PrivateKey, error) {
+	if data, err := ioutil.ReadFile(filename); err == nil {
+		block, _ := pem.Decode(data)
+		if block == nil {
+			return nil, fmt.Errorf("Failed to decode private key: %s", filename)
+		}
+
+		return x509.ParseECPrivateKey(block.Bytes)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeKey(filename, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// writeKey saves the provided EC private key to filename in PEM encoding
+func writeKey(filename string, key *ecdsa.PrivateKey) error {
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+	return ioutil.WriteFile(filename, data, 0600)
+}
+
+// client returns the ACME protocol client, constructing it (and loading the account key)
+// on first use
+func (manager *ACMEManager) client() (*acme.Client, error) {
+	manager.clientMutex.Lock()
+	defer manager.clientMutex.Unlock()
+
+	if manager.Client != nil {
+		return manager.Client, nil
+	}
+
+	if err := os.MkdirAll(manager.certFolder(), 0700); err != nil {
+		return nil, fmt.Errorf("Failed to create certificate folder: %s", err)
+	}
+
+	key, err := loadOrCreateKey(manager.AccountKeyFilename())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to load ACME account key: %s", err)
+	}
+
+	httpClient := http.DefaultClient
+	if manager.DirectoryCAFile != "" {
+		filename := manager.DirectoryCAFile
+		if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
+			filename = GetApplicationPath() + filename[1:]
+		}
+
+		data, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read ACME directory CA file: %s", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("Failed to parse ACME directory CA file: %s", filename)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		httpClient = &http.Client{Transport: transport}
+	}
+
+	manager.Client = &acme.Client{
+		Key:          key,
+		DirectoryURL: manager.DirectoryURL,
+		HTTPClient:   httpClient,
+		UserAgent:    "mvcapp",
+	}
+
+	return manager.Client, nil
+}
+
+// Register creates (or reuses) the ACME account for the account key in CertPath, agreeing
+// to the certificate authority's terms of service
+func (manager *ACMEManager) Register() error {
+	client, err := manager.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), manager.Timeout)
+	defer cancel()
+
+	account := &acme.Account{}
+	if manager.Email != "" {
+		account.Contact = []string{"mailto:" + manager.Email}
+	}
+
+	if _, err := client.Register(ctx, account, acme.AcceptTOS); err != nil && err != acme.ErrAccountAlreadyExists {
+		return fmt.Errorf("Failed to register ACME account: %s", err)
+	}
+
+	LogTrace("ACME account registered")
+	return nil
+}
+
+// HandleChallenge will respond to ACME HTTP-01 challenge requests for the tokens of pending
+// orders. Returns true if the request was handled
+func (manager *ACMEManager) HandleChallenge(response http.ResponseWriter, request *http.Request) bool {
+	if !strings.HasPrefix(request.URL.Path, acmeChallengePrefix) {
+		return false
+	}
+
+	token := strings.TrimPrefix(request.URL.Path, acmeChallengePrefix)
+
+	manager.mutex.RLock()
+	keyAuth, ok := manager.tokens[token]
+	manager.mutex.RUnlock()
+
+	if !ok {
+		LogWarningf("Unknown ACME challenge token requested: %s", token)
+		http.NotFound(response, request)
+		return true
+	}
+
+	response.Header().Set("Content-Type", "text/plain")
+	response.Write([]byte(keyAuth))
+	return true
+}
+
+// setToken registers (or with an empty keyAuth, removes) a pending HTTP-01 challenge token
+func (manager *ACMEManager) setToken(token string, keyAuth string) {
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+
+	if keyAuth == "" {
+		delete(manager.tokens, token)
+		return
+	}
+
+	manager.tokens[token] = keyAuth
+}
+
+// authorize completes the HTTP-01 challenge of the provided authorization url
+func (manager *ACMEManager) authorize(ctx context.Context, client *acme.Client, url string) error {
+	authz, err := client.GetAuthorization(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "http-01" {
+			challenge = c
+			break
+		}
+	}
+
+	if challenge == nil {
+		return fmt.Errorf("No http-01 challenge offered for %s", authz.Identifier.Value)
+	}
+
+	keyAuth, err := client.HTTP01ChallengeResponse(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	manager.setToken(challenge.Token, keyAuth)
+	defer manager.setToken(challenge.Token, "")
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return err
+	}
+
+	_, err = client.WaitAuthorization(ctx, authz.URI)
+	return err
+}
+
+// ObtainCertificate places a new certificate order for Domains, answers the HTTP-01 challenges
+// (the plain http listener must be serving HandleChallenge) and saves the issued certificate
+// to CertPath
+func (manager *ACMEManager) ObtainCertificate() error {
+	if len(manager.Domains) <= 0 {
+		return errors.New("Failed to obtain certificate, no domains provided")
+	}
+
+	if err := manager.Register(); err != nil {
+		return err
+	}
+
+	client, err := manager.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), manager.Timeout)
+	defer cancel()
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(manager.Domains...))
+	if err != nil {
+		return fmt.Errorf("Failed to place certificate order: %s", err)
+	}
+
+	for _, url := range order.AuthzURLs {
+		if err := manager.authorize(ctx, client, url); err != nil {
+			return fmt.Errorf("Failed to authorize certificate order: %s", err)
+		}
+	}
+
+	if _, err := client.WaitOrder(ctx, order.URI); err != nil {
+		return fmt.Errorf("Failed to complete certificate order: %s", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: manager.Domains[0]},
+		DNSNames: manager.Domains,
+	}, crypto.Signer(key))
+	if err != nil {
+		return err
+	}
+
+	chain, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return fmt.Errorf("Failed to finalize certificate order: %s", err)
+	}
+
+	data := []byte{}
+	for _, der := range chain {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+
+	if err := writeKey(manager.KeyFilename(), key); err != nil {
+		return fmt.Errorf("Failed to save certificate key: %s", err)
+	}
+
+	if err := ioutil.WriteFile(manager.CertFilename(), data, 0600); err != nil {
+		return fmt.Errorf("Failed to save certificate: %s", err)
+	}
+
+	LogMessagef("Obtained ACME certificate for: %s", strings.Join(manager.Domains, ", "))
+	return manager.LoadCertificate()
+}
+
+// LoadCertificate reads the certificate and key previously stored in CertPath
+func (manager *ACMEManager) LoadCertificate() error {
+	cert, err := tls.LoadX509KeyPair(manager.CertFilename(), manager.KeyFilename())
+	if err != nil {
+		return err
+	}
+
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return err
+		}
+	}
+
+	manager.mutex.Lock()
+	manager.certificate = &cert
+	manager.mutex.Unlock()
+
+	return nil
+}
+
+// NeedsRenewal returns true if no certificate is loaded or the loaded certificate expires
+// within the RenewBefore duration
+func (manager *ACMEManager) NeedsRenewal() bool {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	if manager.certificate == nil || manager.certificate.Leaf == nil {
+		return true
+	}
+
+	return time.Now().Add(manager.RenewBefore).After(manager.certificate.Leaf.NotAfter)
+}
+
+// RenewCertificate loads the stored certificate if needed, and obtains a new one if it is
+// missing or due for renewal. Returns nil if no renewal was necessary
+func (manager *ACMEManager) RenewCertificate() error {
+	manager.mutex.RLock()
+	loaded := manager.certificate != nil
+	manager.mutex.RUnlock()
+
+	if !loaded {
+		// It's fine if this fails, we'll simply order a new certificate
+		manager.LoadCertificate()
+	}
+
+	if !manager.NeedsRenewal() {
+		return nil
+	}
+
+	LogTrace("ACME certificate missing or due for renewal")
+	return manager.ObtainCertificate()
+}
+
+// GetCertificate returns the currently loaded certificate, it is assigned to the tls.Config of
+// the https server so that renewed certificates are used without restarting
+func (manager *ACMEManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+
+	if manager.certificate == nil {
+		return nil, errors.New("No ACME certificate loaded")
+	}
+
+	return manager.certificate, nil
+}
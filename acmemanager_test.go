@@ -0,0 +1,298 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	ACME Certificate Manager Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of acmemanager.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in acmemanager.go
+*/
+
+package mvcapp_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// The end to end test requires a local Pebble server (https://github.com/letsencrypt/pebble),
+// started with PEBBLE_VA_ALWAYS_VALID=1 or with its http-01 port pointed at acmePebbleHTTPPort.
+// Set doACMEPebbleTests to true to include it with your unit tests.
+const (
+	doACMEPebbleTests   = false
+	acmePebbleDirectory = "https://localhost:14000/dir"
+	acmePebbleCAFile    = "./pebble.minica.pem"
+	acmePebbleHTTPPort  = 5002
+)
+
+// writeTestCertificate is used internally to write a self signed certificate that expires
+// after the provided duration to the managers CertFilename and KeyFilename
+func writeTestCertificate(t *testing.T, manager *mvcapp.ACMEManager, expires time.Duration) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: manager.Domains[0]},
+		DNSNames:     manager.Domains,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(expires),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	os.MkdirAll(fmt.Sprintf("%s/_test_certs", mvcapp.GetApplicationPath()), 0700)
+	if err := ioutil.WriteFile(manager.CertFilename(), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(manager.KeyFilename(), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestNewACMEManagerFromConfig ensures that mvcapp.NewACMEManagerFromConfig returns the expected value
+func TestNewACMEManagerFromConfig(t *testing.T) {
+	config := mvcapp.NewConfigurationManager()
+	config.DomainName = "example.com"
+
+	manager := mvcapp.NewACMEManagerFromConfig(config)
+	if len(manager.Domains) != 1 || manager.Domains[0] != "example.com" {
+		t.Error("Failed to fall back to the configured DomainName")
+	}
+
+	if manager.RenewBefore != 30*24*time.Hour {
+		t.Error("Failed to set renewal duration from configuration")
+	}
+
+	config.ACMEDomains = []string{"www.example.com", "example.com"}
+	manager = mvcapp.NewACMEManagerFromConfig(config)
+	if len(manager.Domains) != 2 || manager.Domains[0] != "www.example.com" {
+		t.Error("Failed to use the configured ACMEDomains")
+	}
+}
+
+// TestACMEManager_HandleChallenge ensures that the ACMEManager.HandleChallenge method operates as expected
+func TestACMEManager_HandleChallenge(t *testing.T) {
+	manager := mvcapp.NewACMEManager()
+
+	req := httptest.NewRequest("GET", "http://localhost/home/index", nil)
+	recorder := httptest.NewRecorder()
+	if manager.HandleChallenge(recorder, req) {
+		t.Error("Failed to ignore a non challenge request")
+	}
+
+	req = httptest.NewRequest("GET", "http://localhost/.well-known/acme-challenge/unknown", nil)
+	recorder = httptest.NewRecorder()
+	if !manager.HandleChallenge(recorder, req) {
+		t.Error("Failed to handle a challenge request")
+	}
+
+	if recorder.Code != 404 {
+		t.Errorf("Failed to refuse unknown challenge token, received status %d", recorder.Code)
+	}
+}
+
+// TestACMEManager_LoadCertificate ensures that the ACMEManager.LoadCertificate, NeedsRenewal and
+// GetCertificate methods operate as expected
+func TestACMEManager_LoadCertificate(t *testing.T) {
+	manager := mvcapp.NewACMEManager()
+	manager.CertPath = "./_test_certs"
+	manager.Domains = []string{"localhost"}
+	defer os.RemoveAll(fmt.Sprintf("%s/_test_certs", mvcapp.GetApplicationPath()))
+
+	if err := manager.LoadCertificate(); err == nil {
+		t.Error("Failed to fail loading a missing certificate")
+	}
+
+	if !manager.NeedsRenewal() {
+		t.Error("Failed to require renewal when no certificate is loaded")
+	}
+
+	if _, err := manager.GetCertificate(nil); err == nil {
+		t.Error("Failed to fail returning a missing certificate")
+	}
+
+	writeTestCertificate(t, manager, 90*24*time.Hour)
+	if err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("Failed to load certificate: %s", err)
+	}
+
+	if manager.NeedsRenewal() {
+		t.Error("Failed to accept a certificate outside the renewal window")
+	}
+
+	if cert, err := manager.GetCertificate(nil); err != nil || cert == nil {
+		t.Errorf("Failed to return loaded certificate: %s", err)
+	}
+
+	if err := manager.RenewCertificate(); err != nil {
+		t.Errorf("Failed to skip renewing a current certificate: %s", err)
+	}
+
+	writeTestCertificate(t, manager, 10*24*time.Hour)
+	if err := manager.LoadCertificate(); err != nil {
+		t.Fatalf("Failed to load certificate: %s", err)
+	}
+
+	if !manager.NeedsRenewal() {
+		t.Error("Failed to require renewal of a certificate inside the renewal window")
+	}
+}
+
+// TestACMEManager_ObtainCertificate ensures that the ACMEManager.ObtainCertificate method fails
+// without domains, and (when enabled) obtains a certificate from a local Pebble server
+func TestACMEManager_ObtainCertificate(t *testing.T) {
+	manager := mvcapp.NewACMEManager()
+	if err := manager.ObtainCertificate(); err == nil {
+		t.Error("Failed to prevent ordering a certificate without domains")
+	}
+
+	if !doACMEPebbleTests {
+		return
+	}
+
+	config := mvcapp.NewConfigurationManager()
+	config.DomainName = "localhost"
+	config.HTTPPort = acmePebbleHTTPPort
+	config.ACMEDirectoryURL = acmePebbleDirectory
+	config.ACMEDirectoryCAFile = acmePebbleCAFile
+	config.ACMECertPath = "./_test_pebble_certs"
+	defer os.RemoveAll(fmt.Sprintf("%s/_test_pebble_certs", mvcapp.GetApplicationPath()))
+
+	app := mvcapp.NewApplicationFromConfig(config)
+	app.ACMEManager = mvcapp.NewACMEManagerFromConfig(config)
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", config.HTTPPort), Handler: http.HandlerFunc(app.RedirectSecure)}
+	go server.ListenAndServe()
+	defer server.Close()
+
+	if err := app.ACMEManager.ObtainCertificate(); err != nil {
+		t.Fatalf("Failed to obtain certificate from Pebble: %s", err)
+	}
+
+	if app.ACMEManager.NeedsRenewal() {
+		t.Error("Failed to load the newly obtained certificate")
+	}
+}
+
+// TestACMEManager_Register ensures that the ACME client is constructed once when the manager is
+// used concurrently (run with -race)
+func TestACMEManager_Register(t *testing.T) {
+	directory := httptest.NewServer(http.NotFoundHandler())
+	defer directory.Close()
+
+	manager := mvcapp.NewACMEManager()
+	manager.CertPath = "./_test_certs"
+	manager.DirectoryURL = directory.URL
+	defer os.RemoveAll(fmt.Sprintf("%s/_test_certs", mvcapp.GetApplicationPath()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := manager.Register(); err == nil {
+				t.Error("Failed to fail registering with a missing directory")
+			}
+		}()
+	}
+
+	wg.Wait()
+	if manager.Client == nil {
+		t.Error("Failed to construct the ACME client")
+	}
+}
+
+// TestApplication_RunForcedSecureACME ensures that a bind error of the http listener is returned
+// before a certificate is ordered
+func TestApplication_RunForcedSecureACME(t *testing.T) {
+	var requests int32
+	directory := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.NotFound(w, req)
+	}))
+	defer directory.Close()
+
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer used.Close()
+
+	app := mvcapp.NewApplication()
+	app.Config.BindAddress = "127.0.0.1"
+	app.Config.HTTPPort = used.Addr().(*net.TCPAddr).Port
+	app.ACMEManager = mvcapp.NewACMEManager()
+	app.ACMEManager.CertPath = "./_test_certs"
+	app.ACMEManager.Domains = []string{"localhost"}
+	app.ACMEManager.DirectoryURL = directory.URL
+	defer os.RemoveAll(fmt.Sprintf("%s/_test_certs", mvcapp.GetApplicationPath()))
+
+	if err := app.RunForcedSecureACME(); err == nil {
+		t.Error("Failed to return the bind error of the http listener")
+	}
+
+	if atomic.LoadInt32(&requests) != 0 || app.HTTPServer != nil {
+		t.Errorf("Failed to stop before ordering a certificate, %d directory requests", requests)
+	}
+}
+
+// TestApplication_RunForcedSecureACMEHTTPSError ensures that both servers are closed and cleared
+// when the https listener fails, so that the application can be run again
+func TestApplication_RunForcedSecureACMEHTTPSError(t *testing.T) {
+	used, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer used.Close()
+
+	app := mvcapp.NewApplication()
+	app.Config.BindAddress = "127.0.0.1"
+	app.Config.HTTPPort = 0
+	app.Config.HTTPSPort = used.Addr().(*net.TCPAddr).Port
+	app.Config.TaskDuration = 0
+	app.ACMEManager = mvcapp.NewACMEManager()
+	app.ACMEManager.CertPath = "./_test_certs"
+	app.ACMEManager.Domains = []string{"localhost"}
+	defer os.RemoveAll(fmt.Sprintf("%s/_test_certs", mvcapp.GetApplicationPath()))
+	writeTestCertificate(t, app.ACMEManager, 90*24*time.Hour)
+
+	for i := 0; i < 2; i++ {
+		err := app.RunForcedSecureACME()
+		if err == nil || strings.Contains(err.Error(), "Server already in use") {
+			t.Fatalf("Failed to return the bind error of the https listener: %v", err)
+		}
+
+		if app.HTTPServer != nil || app.HTTPSServer != nil {
+			t.Fatal("Failed to close and clear the servers after the https listener failed")
+		}
+	}
+}
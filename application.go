@@ -1,253 +1,729 @@
-/*
-	Digivance MVC Application Framework
-	Application object
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines functionality for mapping an action method to an http request optionally
-	boud to an http verb.
-*/
-
-package mvcapp
-
-import (
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
-	"time"
-)
-
-// Application is our global scope object (E.g. application wide configuration
-// and manager systems)
-type Application struct {
-	// RouteManager is our Route Manager system
-	RouteManager *RouteManager
-
-	Config *ConfigurationManager
-
-	// HTTPServer is the http.Server object being used to host http transport
-	HTTPServer *http.Server
-
-	// HTTPSServer is the http.Server object being used to host https transport
-	HTTPSServer *http.Server
-}
-
-// NewApplication returns a new default MVC Application object
-func NewApplication() *Application {
-	rtn := &Application{
-		RouteManager: NewRouteManager(),
-		Config:       NewConfigurationManager(),
-		HTTPServer:   nil,
-		HTTPSServer:  nil,
-	}
-
-	if LogFilename == "" {
-		SetLogFilename("./mvcapp.log")
-	}
-
-	LogTrace("Application initialized")
-	return rtn
-}
-
-// NewApplicationFromConfig returns a new MVC Application object with the provided
-// configuration manager object
-func NewApplicationFromConfig(config *ConfigurationManager) *Application {
-	rtn := &Application{
-		RouteManager: NewRouteManager(),
-		Config:       config,
-		HTTPServer:   nil,
-		HTTPSServer:  nil,
-	}
-
-	if LogFilename == "" {
-		SetLogFilename("./mvcapp.log")
-	}
-
-	rtn.RouteManager.SessionManager.SessionTimeout = time.Duration(config.HTTPSessionTimeout) * time.Minute
-
-	LogTrace("Application initialized")
-	return rtn
-}
-
-// NewApplicationFromConfigFile returns a new MVC Application object  constructed
-// from the provided json config file
-func NewApplicationFromConfigFile(filename string) (*Application, error) {
-	config, err := NewConfigurationManagerFromFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	return NewApplicationFromConfig(config), nil
-}
-
-// Stop is used to stop hosting this MVC Application. You can call one of the Run methods to restart
-func (app *Application) Stop() error {
-	if app.HTTPServer != nil {
-		app.HTTPServer.Shutdown(nil)
-	}
-
-	if app.HTTPSServer != nil {
-		if err := app.HTTPSServer.Shutdown(nil); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// Run is used to execute this MVC Application (direct http socket server)
-func (app *Application) Run() error {
-	if app.HTTPServer != nil {
-		return errors.New("Can not run application, HTTPServer already in use")
-	}
-
-	config := app.Config
-	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
-	app.HTTPServer = &http.Server{Addr: addr}
-	app.HTTPServer.Handler = http.HandlerFunc(app.RouteManager.HandleRequest)
-
-	return app.HTTPServer.ListenAndServe()
-}
-
-// RunSecure is used to execute this MVC Application over HTTPS/TLS (direct https socket server)
-func (app *Application) RunSecure(certFile string, keyFile string) error {
-	if app.HTTPSServer != nil {
-		return errors.New("Can not RunSecure, HTTPSServer already in use")
-	}
-
-	config := app.Config
-	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
-	app.HTTPSServer = &http.Server{Addr: addr}
-	app.HTTPSServer.Handler = http.HandlerFunc(app.RouteManager.HandleRequest)
-
-	return app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
-}
-
-// RunForcedSecure is used to execute this MVC Application in both HTTP and
-// HTTPS/TLS modes, the HTTP mode will force redirection to HTTPS only. (direct http and https
-// socket servers)
-func (app *Application) RunForcedSecure(certFile string, keyFile string) error {
-	if app.HTTPServer != nil {
-		return errors.New("Can not RunForcedSecure, HTTPServer already in use")
-	}
-
-	if app.HTTPSServer != nil {
-		return errors.New("Can not RunForcedSecure, HTTPSServer already in use")
-	}
-
-	var err error
-
-	config := app.Config
-	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
-	app.HTTPServer = &http.Server{Addr: addr}
-	app.HTTPServer.Handler = http.HandlerFunc(app.RedirectSecure)
-	go func() {
-		err = app.HTTPServer.ListenAndServe()
-	}()
-
-	addr = fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
-	app.HTTPSServer = &http.Server{Addr: addr}
-	app.HTTPSServer.Handler = http.HandlerFunc(app.RouteManager.HandleRequest)
-	go func() {
-		if certFile == "" {
-			certFile = config.TLSCertFile
-		}
-
-		if keyFile == "" {
-			keyFile = config.TLSKeyFile
-		}
-
-		err = app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
-	}()
-
-	// Here is an internal management thread if needed, it waits for app.Config.TaskDuration per tick
-	for err == nil {
-		time.Sleep(time.Duration(config.TaskDuration) * time.Second)
-	}
-
-	if r := recover(); r != nil {
-		err, ok := r.(error)
-		if !ok {
-			err = fmt.Errorf("Failed to launch application in forced secure mode: %s", err)
-		}
-
-		return err
-	}
-
-	return err
-}
-
-// RunForcedSecureJS is used to run a web application in forced TLS secure mode by returning
-// a simple page with javascript that redirects the browser to https://DomainName/path
-func (app *Application) RunForcedSecureJS(certFile string, keyFile string) error {
-	if app.HTTPServer != nil {
-		return errors.New("Can not RunForcedSecureJS, HTTPServer already in use")
-	}
-
-	if app.HTTPSServer != nil {
-		return errors.New("Can not RunForcedSecure, HTTPSServer already in use")
-	}
-
-	var err error
-
-	config := app.Config
-	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
-	app.HTTPServer = &http.Server{Addr: addr}
-	app.HTTPServer.Handler = http.HandlerFunc(app.RedirectSecureJS)
-	go func() {
-		err = app.HTTPServer.ListenAndServe()
-	}()
-
-	addr = fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
-	app.HTTPSServer = &http.Server{Addr: addr}
-	app.HTTPSServer.Handler = http.HandlerFunc(app.RouteManager.HandleRequest)
-	go func() {
-		err = app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
-	}()
-
-	for err == nil {
-		time.Sleep(time.Duration(config.TaskDuration) * time.Second)
-	}
-
-	if r := recover(); r != nil {
-		err, ok := r.(error)
-		if !ok {
-			err = fmt.Errorf("Failed to launch application in forced secure via javascript mode: %s", err)
-		}
-
-		return err
-	}
-
-	return err
-}
-
-// RedirectSecure is used to submit an http redirect from http to https when forcing secure
-func (app *Application) RedirectSecure(w http.ResponseWriter, req *http.Request) {
-	// To allow for google site ownership verification
-	if app.Config.AllowGoogleAuthFiles && strings.HasPrefix(req.URL.Path, "google") && strings.HasSuffix(req.URL.Path, ".html") {
-		path := fmt.Sprintf("%s/%s", GetApplicationPath(), strings.TrimLeft(req.URL.Path, "/"))
-		http.ServeFile(w, req, path)
-		return
-	}
-
-	target := fmt.Sprintf("https://%s%s", req.Host, req.URL.Path)
-
-	if len(req.URL.RawQuery) > 0 {
-		target += "?" + req.URL.RawQuery
-	}
-
-	http.Redirect(w, req, target, http.StatusTemporaryRedirect)
-}
-
-// RedirectSecureJS is used to submit a javascript redirect from http to https when forcing secure
-func (app *Application) RedirectSecureJS(w http.ResponseWriter, req *http.Request) {
-	// To allow for google site ownership verification
-	if app.Config.AllowGoogleAuthFiles && strings.HasPrefix(req.URL.Path, "/google") && strings.HasSuffix(req.URL.Path, ".html") {
-		path := fmt.Sprintf("%s/%s", GetApplicationPath(), strings.TrimLeft(req.URL.Path, "/"))
-		http.ServeFile(w, req, path)
-		return
-	}
-
-	data := fmt.Sprintf("<html><head><title>Redirecting to secure site mode</title></head><body><script type=\"text/javascript\">window.location.href='https://%s%s';</script></body>", req.Host, req.URL.Path)
-	w.Write([]byte(data))
-}
+/*
+	Digivance MVC Application Framework
+	Application object
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines functionality for mapping an action method to an http request optionally
+	boud to an http verb.
+*/
+
+package mvcapp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Application is our global scope object (E.g. application wide configuration
+// and manager systems)
+type Application struct {
+	// RouteManager is our Route Manager system
+	RouteManager *RouteManager
+
+	Config *ConfigurationManager
+
+	// HTTPServer is the http.Server object being used to host http transport
+	HTTPServer *http.Server
+
+	// HTTPSServer is the http.Server object being used to host https transport
+	HTTPSServer *http.Server
+
+	// Listeners is the collection of addresses served by RunListeners
+	Listeners []*Listener
+
+	// ACMEManager is used to obtain and renew certificates when running via RunForcedSecureACME,
+	// its HTTP-01 challenges are answered by the RedirectSecure handlers
+	ACMEManager *ACMEManager
+
+	// Watcher polls the bundle sources, views and configuration files for changes in
+	// DevelopmentMode, rebuilding the affected bundles and refreshing open browsers (nil
+	// when not in DevelopmentMode)
+	Watcher *Watcher
+}
+
+// NewApplication returns a new default MVC Application object
+func NewApplication() *Application {
+	rtn := &Application{
+		RouteManager: NewRouteManager(),
+		Config:       NewConfigurationManager(),
+		HTTPServer:   nil,
+		HTTPSServer:  nil,
+		Listeners:    make([]*Listener, 0),
+	}
+
+	if LogFilename == "" {
+		SetLogFilename("./mvcapp.log")
+	}
+
+	LogTrace("Application initialized")
+	return rtn
+}
+
+// NewApplicationFromConfig returns a new MVC Application object with the provided
+// configuration manager object
+func NewApplicationFromConfig(config *ConfigurationManager) *Application {
+	rtn := &Application{
+		RouteManager: NewRouteManager(),
+		Config:       config,
+		HTTPServer:   nil,
+		HTTPSServer:  nil,
+		Listeners:    make([]*Listener, 0),
+	}
+
+	rtn.Listeners = append(rtn.Listeners, config.Listeners...)
+
+	if LogFilename == "" {
+		SetLogFilename("./mvcapp.log")
+	}
+
+	rtn.RouteManager.SessionManager.SessionTimeout = time.Duration(config.HTTPSessionTimeout) * time.Minute
+
+	rtn.RouteManager.ViewMinifier = NewViewMinifierFromConfig(config)
+	rtn.RouteManager.BundleManager = NewBundleManagerFromConfig(config)
+
+	// In development mode the Watcher (see StartWatcher) reloads the manifest
+	if rtn.RouteManager.BundleManager.manifestFilename() != "" && config.TaskDuration > 0 && !config.DevelopmentMode {
+		rtn.RouteManager.BundleManager.WatchManifest(time.Duration(config.TaskDuration) * time.Second)
+	}
+
+	proxies, err := ParseTrustedProxies(config.TrustedProxies)
+	if err != nil {
+		LogErrorf("Failed to load trusted proxies: %s", err)
+	} else {
+		rtn.RouteManager.TrustedProxies = proxies
+	}
+
+	rtn.RouteManager.ViewCache = NewViewCacheFromConfig(config)
+	rtn.RouteManager.DefaultLayout = config.DefaultLayout
+	rtn.RouteManager.ViewConfig = NewViewConfig(config)
+	rtn.RouteManager.Localizer = NewLocalizerFromConfig(config)
+	if config.DevelopmentMode {
+		rtn.StartWatcher()
+	}
+
+	LogTrace("Application initialized")
+	return rtn
+}
+
+// NewApplicationFromConfigFile returns a new MVC Application object  constructed
+// from the provided json config file
+func NewApplicationFromConfigFile(filename string) (*Application, error) {
+	config, err := NewConfigurationManagerFromFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewApplicationFromConfig(config), nil
+}
+
+// StartWatcher starts polling the content bundle sources, views, message catalogs and
+// configuration files for changes using the WatchInterval and WatchDebounce configuration
+// values. Changed bundles are rebuilt, a changed bundle manifest and message catalogs are
+// reloaded and open browsers are told to refresh through the RouteManager's LiveReload
+// endpoint. Subscribe to app.Watcher for other change handling
+func (app *Application) StartWatcher() {
+	if app.Watcher != nil {
+		app.Watcher.Stop()
+	}
+
+	bundleManager := app.RouteManager.BundleManager
+	if bundleManager != nil {
+		// The manifest is reloaded by the Watcher from now on
+		bundleManager.StopWatchingManifest()
+	}
+
+	if app.RouteManager.LiveReload == nil {
+		app.RouteManager.LiveReload = NewLiveReload()
+	}
+
+	app.Watcher = NewWatcherFromConfig(app.Config)
+	app.Watcher.Watch(WatchKindView, "views/**")
+	app.Watcher.WatchFunc(WatchKindConfig, func() []string {
+		rtn := []string{}
+		if app.Config.Filename() != "" {
+			rtn = append(rtn, app.Config.Filename())
+		}
+
+		if bundleManager != nil {
+			if filename := bundleManager.manifestFilename(); filename != "" {
+				rtn = append(rtn, filename)
+			}
+		}
+
+		return rtn
+	})
+
+	if bundleManager != nil {
+		app.Watcher.WatchFunc(WatchKindBundle, bundleManager.SourceFilenames)
+		app.Watcher.Subscribe(func(event *WatchEvent) {
+			var err error
+			switch event.Kind {
+			case WatchKindBundle:
+				err = bundleManager.RebuildAffectedBundles(event.Files)
+			case WatchKindConfig:
+				err = bundleManager.ReloadManifest()
+			}
+
+			if err != nil {
+				LogErrorf("Failed to apply %s changes: %s", event.Kind, err)
+			}
+		})
+	}
+
+	if localizer := app.RouteManager.Localizer; localizer != nil && app.Config.LocalesPath != "" {
+		app.Watcher.Watch(WatchKindLocale, strings.TrimPrefix(strings.TrimPrefix(app.Config.LocalesPath, "./"), "~/")+"/**")
+		app.Watcher.Subscribe(func(event *WatchEvent) {
+			if event.Kind != WatchKindLocale {
+				return
+			}
+
+			if err := localizer.Reload(); err != nil {
+				LogErrorf("Failed to apply %s changes: %s", event.Kind, err)
+			}
+		})
+	}
+
+	viewCache := app.RouteManager.ViewCache
+	app.Watcher.Subscribe(func(event *WatchEvent) {
+		if event.Kind == WatchKindView && viewCache != nil {
+			viewCache.Invalidate()
+		}
+	})
+
+	liveReload := app.RouteManager.LiveReload
+	app.Watcher.Subscribe(func(event *WatchEvent) {
+		liveReload.Notify(event.Kind)
+	})
+
+	app.Watcher.Start()
+}
+
+// UseFileSystem reads the application files (views, static files, bundle sources and the bundle
+// manifest) from the provided file system (E.g. an embed.FS), see mvcapp.UseFileSystem. In
+// DevelopmentMode the files on the disk take precedence over the provided file system
+func (app *Application) UseFileSystem(fsys fs.FS) {
+	UseFileSystem(fsys, app.Config.DevelopmentMode)
+	if app.RouteManager.ViewCache != nil {
+		app.RouteManager.ViewCache.Invalidate()
+	}
+}
+
+// PrecompileViews parses every template in the views folder into the RouteManager's ViewCache, returning
+// an error listing the templates that fail to parse so that broken views are found at startup
+func (app *Application) PrecompileViews() error {
+	dir := fmt.Sprintf("%s/views", GetApplicationPath())
+	if _, err := statFile(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	return app.RouteManager.ViewCache.PrecompileDirectory(dir)
+}
+
+// startup is called by the Run methods before they start serving. Outside of DevelopmentMode the
+// views are precompiled (see PrecompileViews) from the file system set by UseFileSystem, a view
+// that fails to parse stops the application
+func (app *Application) startup() error {
+	if app.Config.DevelopmentMode || app.RouteManager.ViewCache == nil {
+		return nil
+	}
+
+	return app.PrecompileViews()
+}
+
+// Stop is used to stop hosting this MVC Application. You can call one of the Run methods to restart
+func (app *Application) Stop() error {
+	if app.Watcher != nil {
+		app.Watcher.Stop()
+	}
+
+	if app.RouteManager.BundleManager != nil {
+		app.RouteManager.BundleManager.StopWatchingManifest()
+	}
+
+	if app.RouteManager.LiveReload != nil {
+		app.RouteManager.LiveReload.Close()
+	}
+
+	if app.HTTPServer != nil {
+		app.HTTPServer.Shutdown(nil)
+	}
+
+	if app.HTTPSServer != nil {
+		if err := app.HTTPSServer.Shutdown(nil); err != nil {
+			return err
+		}
+	}
+
+	for _, listener := range app.Listeners {
+		if err := listener.Shutdown(context.Background()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddListener adds a listener to the collection served by RunListeners
+func (app *Application) AddListener(listener *Listener) {
+	app.Listeners = append(app.Listeners, listener)
+}
+
+// ListenerHandler returns the http handler for the provided listener, serving its subset of
+// controllers (see HandleSecureRequest) or redirecting to https when the listener is
+// ForceSecure (see RedirectSecure). Plain http listeners behind a trusted https proxy also
+// write the Strict-Transport-Security header and redirect to the canonical host
+func (app *Application) ListenerHandler(listener *Listener) http.Handler {
+	routes := app.RouteManager.Subset(listener.Controllers)
+
+	if listener.ForceSecure {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			app.redirectSecure(routes, w, req)
+		})
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		app.handleSecure(routes, w, req)
+	})
+}
+
+// RunListeners is used to execute this MVC Application on each of the registered Listeners at
+// once. Once every listener is open, a process started by Restart signals that it is ready.
+// Returns when any of the listeners fail, or nil once all of them are stopped and drained
+func (app *Application) RunListeners() error {
+	if len(app.Listeners) <= 0 {
+		return errors.New("Can not RunListeners, no listeners registered")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	for _, listener := range app.Listeners {
+		if err := listener.Open(); err != nil {
+			return fmt.Errorf("Listener %s failed: %s", listener.Name, err)
+		}
+	}
+
+	if err := NotifyReady(); err != nil {
+		LogError(err.Error())
+	}
+
+	errs := make(chan error, len(app.Listeners))
+	for _, listener := range app.Listeners {
+		go func(listener *Listener) {
+			err := listener.Serve(app.ListenerHandler(listener))
+			if err != nil {
+				err = fmt.Errorf("Listener %s failed: %s", listener.Name, err)
+			}
+
+			errs <- err
+		}(listener)
+	}
+
+	for range app.Listeners {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Run is used to execute this MVC Application (direct http socket server). Requests that a
+// trusted proxy received over https are handled as by HandleSecureRequest
+func (app *Application) Run() error {
+	if app.HTTPServer != nil {
+		return errors.New("Can not run application, HTTPServer already in use")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	config := app.Config
+	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
+	app.HTTPServer = &http.Server{Addr: addr}
+	app.HTTPServer.Handler = http.HandlerFunc(app.HandleSecureRequest)
+
+	return app.HTTPServer.ListenAndServe()
+}
+
+// RunSecure is used to execute this MVC Application over HTTPS/TLS (direct https socket server)
+func (app *Application) RunSecure(certFile string, keyFile string) error {
+	if app.HTTPSServer != nil {
+		return errors.New("Can not RunSecure, HTTPSServer already in use")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	config := app.Config
+	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
+	app.HTTPSServer = &http.Server{Addr: addr}
+	app.HTTPSServer.Handler = http.HandlerFunc(app.HandleSecureRequest)
+
+	return app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
+}
+
+// RunForcedSecure is used to execute this MVC Application in both HTTP and
+// HTTPS/TLS modes, the HTTP mode will force redirection to HTTPS only. (direct http and https
+// socket servers)
+func (app *Application) RunForcedSecure(certFile string, keyFile string) error {
+	if app.HTTPServer != nil {
+		return errors.New("Can not RunForcedSecure, HTTPServer already in use")
+	}
+
+	if app.HTTPSServer != nil {
+		return errors.New("Can not RunForcedSecure, HTTPSServer already in use")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	var err error
+
+	config := app.Config
+	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
+	app.HTTPServer = &http.Server{Addr: addr}
+	app.HTTPServer.Handler = http.HandlerFunc(app.RedirectSecure)
+	go func() {
+		err = app.HTTPServer.ListenAndServe()
+	}()
+
+	addr = fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
+	app.HTTPSServer = &http.Server{Addr: addr}
+	app.HTTPSServer.Handler = http.HandlerFunc(app.HandleSecureRequest)
+	go func() {
+		if certFile == "" {
+			certFile = config.TLSCertFile
+		}
+
+		if keyFile == "" {
+			keyFile = config.TLSKeyFile
+		}
+
+		err = app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
+	}()
+
+	// Here is an internal management thread if needed, it waits for app.Config.TaskDuration per tick
+	for err == nil {
+		time.Sleep(time.Duration(config.TaskDuration) * time.Second)
+	}
+
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("Failed to launch application in forced secure mode: %s", err)
+		}
+
+		return err
+	}
+
+	return err
+}
+
+// RunForcedSecureJS is used to run a web application in forced TLS secure mode by returning
+// a simple page with javascript that redirects the browser to https://DomainName/path
+func (app *Application) RunForcedSecureJS(certFile string, keyFile string) error {
+	if app.HTTPServer != nil {
+		return errors.New("Can not RunForcedSecureJS, HTTPServer already in use")
+	}
+
+	if app.HTTPSServer != nil {
+		return errors.New("Can not RunForcedSecure, HTTPSServer already in use")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	var err error
+
+	config := app.Config
+	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
+	app.HTTPServer = &http.Server{Addr: addr}
+	app.HTTPServer.Handler = http.HandlerFunc(app.RedirectSecureJS)
+	go func() {
+		err = app.HTTPServer.ListenAndServe()
+	}()
+
+	addr = fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
+	app.HTTPSServer = &http.Server{Addr: addr}
+	app.HTTPSServer.Handler = http.HandlerFunc(app.HandleSecureRequest)
+	go func() {
+		err = app.HTTPSServer.ListenAndServeTLS(certFile, keyFile)
+	}()
+
+	for err == nil {
+		time.Sleep(time.Duration(config.TaskDuration) * time.Second)
+	}
+
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("Failed to launch application in forced secure via javascript mode: %s", err)
+		}
+
+		return err
+	}
+
+	return err
+}
+
+// RunForcedSecureACME is used to execute this MVC Application in forced secure mode (see
+// RunForcedSecure) using certificates obtained from the configured ACME certificate authority.
+// Certificates are stored in ACMECertPath and are renewed from the internal management thread
+func (app *Application) RunForcedSecureACME() error {
+	if app.HTTPServer != nil {
+		return errors.New("Can not RunForcedSecureACME, HTTPServer already in use")
+	}
+
+	if app.HTTPSServer != nil {
+		return errors.New("Can not RunForcedSecureACME, HTTPSServer already in use")
+	}
+
+	if err := app.startup(); err != nil {
+		return err
+	}
+
+	config := app.Config
+	if app.ACMEManager == nil {
+		app.ACMEManager = NewACMEManagerFromConfig(config)
+	}
+
+	errs := make(chan error, 2)
+
+	// The plain http listener must be running before we order, it answers the HTTP-01 challenges.
+	// The socket is bound first so that a bind error is returned rather than ordering anyway
+	addr := fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPPort)
+	socket, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("Can not RunForcedSecureACME, failed to listen on %s: %s", addr, err)
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: http.HandlerFunc(app.RedirectSecure)}
+	app.HTTPServer = httpServer
+	go func() {
+		errs <- httpServer.Serve(socket)
+	}()
+
+	if err = app.ACMEManager.RenewCertificate(); err != nil {
+		app.closeServers()
+		return err
+	}
+
+	addr = fmt.Sprintf("%s:%d", config.BindAddress, config.HTTPSPort)
+	httpsServer := &http.Server{Addr: addr, Handler: http.HandlerFunc(app.HandleSecureRequest)}
+	httpsServer.TLSConfig = &tls.Config{GetCertificate: app.ACMEManager.GetCertificate}
+	app.HTTPSServer = httpsServer
+	go func() {
+		errs <- httpsServer.ListenAndServeTLS("", "")
+	}()
+
+	// Internal management thread, checks for certificate renewal every app.Config.TaskDuration
+	interval := time.Duration(config.TaskDuration) * time.Second
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for err == nil {
+		select {
+		case err = <-errs:
+		case <-ticker.C:
+			if renewErr := app.ACMEManager.RenewCertificate(); renewErr != nil {
+				LogErrorf("Failed to renew ACME certificate: %s", renewErr)
+			}
+		}
+	}
+
+	app.closeServers()
+	return err
+}
+
+// closeServers closes the HTTPServer and HTTPSServer started by the Run methods, if any, and
+// clears them so that the application can be run again
+func (app *Application) closeServers() {
+	if app.HTTPServer != nil {
+		app.HTTPServer.Close()
+		app.HTTPServer = nil
+	}
+
+	if app.HTTPSServer != nil {
+		app.HTTPSServer.Close()
+		app.HTTPSServer = nil
+	}
+}
+
+// CanonicalHost returns the host name (without port) that requests should be served from, the
+// DomainName is used when CanonicalRedirect is set and the WWWPolicy is then applied
+func (app *Application) CanonicalHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	if app.Config.CanonicalRedirect && app.Config.DomainName != "" {
+		host = app.Config.DomainName
+	}
+
+	switch strings.ToLower(app.Config.WWWPolicy) {
+	case "www":
+		if !strings.HasPrefix(strings.ToLower(host), "www.") {
+			host = "www." + host
+		}
+	case "non-www":
+		if strings.HasPrefix(strings.ToLower(host), "www.") {
+			host = host[4:]
+		}
+	}
+
+	return host
+}
+
+// SecureURL returns the https url of the provided request on the canonical host and the
+// configured HTTPSPort, keeping the requested path and query string. The host is taken from
+// the forwarding headers when the request arrives from a trusted proxy, the port is omitted
+// when the proxy received the request over https as the HTTPSPort is then not the public port
+func (app *Application) SecureURL(req *http.Request) string {
+	forwarded := app.RouteManager.TrustedProxies.Resolve(req)
+	host := app.CanonicalHost(forwarded.Host)
+	if forwarded.Scheme != "https" && app.Config.HTTPSPort != 0 && app.Config.HTTPSPort != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(app.Config.HTTPSPort))
+	}
+
+	target := &url.URL{
+		Scheme:   "https",
+		Host:     host,
+		Path:     req.URL.Path,
+		RawPath:  req.URL.RawPath,
+		RawQuery: req.URL.RawQuery,
+	}
+
+	return target.String()
+}
+
+// redirectStatus returns the configured HTTPSRedirectStatus, falling back to 307 if it is not
+// a redirection status code
+func (app *Application) redirectStatus() int {
+	switch app.Config.HTTPSRedirectStatus {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return app.Config.HTTPSRedirectStatus
+	}
+
+	return http.StatusTemporaryRedirect
+}
+
+// StrictTransportSecurity returns the value of the Strict-Transport-Security header built from
+// the HSTS configuration values, or an empty string if HSTSMaxAge is 0
+func (app *Application) StrictTransportSecurity() string {
+	if app.Config.HSTSMaxAge <= 0 {
+		return ""
+	}
+
+	rtn := fmt.Sprintf("max-age=%d", app.Config.HSTSMaxAge)
+	if app.Config.HSTSIncludeSubDomains {
+		rtn += "; includeSubDomains"
+	}
+
+	if app.Config.HSTSPreload {
+		rtn += "; preload"
+	}
+
+	return rtn
+}
+
+// HandleSecureRequest is the handler of the https server. It writes the Strict-Transport-Security
+// header, redirects requests to the canonical host and passes everything else to the route manager
+func (app *Application) HandleSecureRequest(w http.ResponseWriter, req *http.Request) {
+	app.handleSecure(app.RouteManager, w, req)
+}
+
+// handleSecure implements HandleSecureRequest for the provided route manager (E.g. the route
+// subset of a listener)
+func (app *Application) handleSecure(routes *RouteManager, w http.ResponseWriter, req *http.Request) {
+	forwarded := app.RouteManager.TrustedProxies.Resolve(req)
+	if forwarded.Scheme == "https" {
+		if hsts := app.StrictTransportSecurity(); hsts != "" {
+			w.Header().Set("Strict-Transport-Security", hsts)
+		}
+
+		host := forwarded.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+
+		if !strings.EqualFold(host, app.CanonicalHost(forwarded.Host)) {
+			http.Redirect(w, req, app.SecureURL(req), app.redirectStatus())
+			return
+		}
+	}
+
+	routes.HandleRequest(w, req)
+}
+
+// serveInsecureExceptions serves the requests that are allowed over plain http when forcing secure
+// (ACME challenges and google site ownership files), and requests that a trusted proxy received
+// over https. Returns true if the request was handled
+func (app *Application) serveInsecureExceptions(routes *RouteManager, w http.ResponseWriter, req *http.Request) bool {
+	// The client is already using https to a trusted proxy, redirecting would loop forever
+	if app.RouteManager.TrustedProxies.Resolve(req).Scheme == "https" {
+		app.handleSecure(routes, w, req)
+		return true
+	}
+
+	// To allow the ACME certificate authority to validate HTTP-01 challenges
+	if app.ACMEManager != nil && app.ACMEManager.HandleChallenge(w, req) {
+		return true
+	}
+
+	// To allow for google site ownership verification
+	if app.Config.AllowGoogleAuthFiles && strings.HasPrefix(req.URL.Path, "/google") && strings.HasSuffix(req.URL.Path, ".html") {
+		path := fmt.Sprintf("%s/%s", GetApplicationPath(), strings.TrimLeft(req.URL.Path, "/"))
+		serveFile(w, req, path)
+		return true
+	}
+
+	return false
+}
+
+// RedirectSecure is used to submit an http redirect from http to https when forcing secure. The
+// target uses the canonical host and HTTPSPort, and the configured HTTPSRedirectStatus
+func (app *Application) RedirectSecure(w http.ResponseWriter, req *http.Request) {
+	app.redirectSecure(app.RouteManager, w, req)
+}
+
+// redirectSecure implements RedirectSecure for the provided route manager (E.g. the route
+// subset of a listener)
+func (app *Application) redirectSecure(routes *RouteManager, w http.ResponseWriter, req *http.Request) {
+	if app.serveInsecureExceptions(routes, w, req) {
+		return
+	}
+
+	http.Redirect(w, req, app.SecureURL(req), app.redirectStatus())
+}
+
+// RedirectSecureJS is used to submit a javascript redirect from http to https when forcing secure
+func (app *Application) RedirectSecureJS(w http.ResponseWriter, req *http.Request) {
+	if app.serveInsecureExceptions(app.RouteManager, w, req) {
+		return
+	}
+
+	target := template.JSEscapeString(app.SecureURL(req))
+	data := fmt.Sprintf("<html><head><title>Redirecting to secure site mode</title></head><body><script type=\"text/javascript\">window.location.href='%s';</script></body></html>", target)
+	w.Write([]byte(data))
+}
@@ -1,111 +1,274 @@
-/*
-	Digivance MVC Application Framework - Unit Tests
-	Base Controller Tests
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines the version 0.2.0 compatibility of controller.go functions. These functions are written
-	to demonstrate and test the intended use cases of the functions in controller.go
-*/
-
-package mvcapp_test
-
-import (
-	"io/ioutil"
-	"net/http"
-	"os"
-	"testing"
-
-	"github.com/digivance/mvcapp"
-)
-
-// TestNewApplication ensures that mvcapp.NewApplication returns the expected value
-func TestNewApplication(t *testing.T) {
-	app := mvcapp.NewApplication()
-	if app == nil {
-		t.Error("Failed to create new mvcapp Application object")
-	}
-}
-
-// TestNewApplicationFromConfig ensures that mvcapp.NewApplicationFromConfig returns the expected value
-func TestNewApplicationFromConfig(t *testing.T) {
-	mvcapp.LogFilename = ""
-	config := mvcapp.NewConfigurationManager()
-	app := mvcapp.NewApplicationFromConfig(config)
-	if app == nil {
-		t.Error("Failed to create new mvcapp Application object from provided configuration")
-	}
-}
-
-// TestNewApplicationFromConfigFile ensures that mvcapp.NewApplicationFromConfigFile returns
-// the expected value
-func TestNewApplicationFromConfigFile(t *testing.T) {
-	configFile := mvcapp.GetApplicationPath() + "/testconfig.json"
-	configData := []byte("{\"HTTPPort\": 80,\"HTTPSPort\": 443,\"LogFilename\": \"./app.log\",\"LogLevel\": 4,\"TLSCertFile\": \"./mycert.crt\",\"TLSKeyFile\": \"./mycert.key\"}")
-	err := ioutil.WriteFile(configFile, configData, 0644)
-	defer os.RemoveAll(configFile)
-
-	if err != nil {
-		t.Errorf("Failed to create new configuration manager json file: %s", err)
-	}
-
-	app, err := mvcapp.NewApplicationFromConfigFile("▒▓╜│:)")
-	if err == nil {
-		t.Error("Failed to fail on invalid filename")
-	}
-
-	app, err = mvcapp.NewApplicationFromConfigFile(configFile)
-	if err != nil {
-		t.Errorf("Failed to create new MVC Application object from provided json config file: %s", err)
-	}
-
-	if app.Config.HTTPSPort != 443 {
-		t.Error("Failed to create new MVC Application object from provided json config file: values don't match")
-	}
-}
-
-// appTestController is used to invoke default 404 functionality and test the http server
-type appTestController struct {
-	*mvcapp.Controller
-}
-
-// newAppTestController is the controller creator used to return our internal testController
-func newAppTestController(request *http.Request) mvcapp.IController {
-	return &testController{
-		Controller: mvcapp.NewBaseController(request),
-	}
-}
-
-// TestApplication_Run ensures that the Application.Run method operates as expected
-func TestApplication_Run(t *testing.T) {
-	app := mvcapp.NewApplication()
-	app.Config.HTTPPort = 8906
-	app.RouteManager.RegisterController("Home", newAppTestController)
-
-	if app == nil || app.Config.HTTPPort != 8906 {
-		t.Fatal("Failed to create mvcapp Application object")
-	}
-
-	go func() {
-		app.Run()
-	}()
-
-	res, err := http.Get("http://localhost:8906")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	defer res.Body.Close()
-	defer app.Stop()
-
-	data, err := ioutil.ReadAll(res.Body)
-	actualBody := string(data)
-
-	if len(actualBody) < 50 {
-		t.Error("Did not receive expected 404 result")
-		t.Log(actualBody)
-	}
-
-	if err := app.Run(); err == nil {
-		t.Error("Failed to block & return error when HTTPServer is clearly in use")
-	}
-}
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Base Controller Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.2.0 compatibility of controller.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in controller.go
+*/
+
+package mvcapp_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestNewApplication ensures that mvcapp.NewApplication returns the expected value
+func TestNewApplication(t *testing.T) {
+	app := mvcapp.NewApplication()
+	if app == nil {
+		t.Error("Failed to create new mvcapp Application object")
+	}
+}
+
+// TestNewApplicationFromConfig ensures that mvcapp.NewApplicationFromConfig returns the expected value
+func TestNewApplicationFromConfig(t *testing.T) {
+	mvcapp.LogFilename = ""
+	config := mvcapp.NewConfigurationManager()
+	app := mvcapp.NewApplicationFromConfig(config)
+	if app == nil {
+		t.Error("Failed to create new mvcapp Application object from provided configuration")
+	}
+}
+
+// TestNewApplicationFromConfigFile ensures that mvcapp.NewApplicationFromConfigFile returns
+// the expected value
+func TestNewApplicationFromConfigFile(t *testing.T) {
+	configFile := mvcapp.GetApplicationPath() + "/testconfig.json"
+	configData := []byte("{\"HTTPPort\": 80,\"HTTPSPort\": 443,\"LogFilename\": \"./app.log\",\"LogLevel\": 4,\"TLSCertFile\": \"./mycert.crt\",\"TLSKeyFile\": \"./mycert.key\"}")
+	err := ioutil.WriteFile(configFile, configData, 0644)
+	defer os.RemoveAll(configFile)
+
+	if err != nil {
+		t.Errorf("Failed to create new configuration manager json file: %s", err)
+	}
+
+	app, err := mvcapp.NewApplicationFromConfigFile("▒▓╜│:)")
+	if err == nil {
+		t.Error("Failed to fail on invalid filename")
+	}
+
+	app, err = mvcapp.NewApplicationFromConfigFile(configFile)
+	if err != nil {
+		t.Errorf("Failed to create new MVC Application object from provided json config file: %s", err)
+	}
+
+	if app.Config.HTTPSPort != 443 {
+		t.Error("Failed to create new MVC Application object from provided json config file: values don't match")
+	}
+}
+
+// appTestController is used to invoke default 404 functionality and test the http server
+type appTestController struct {
+	*mvcapp.Controller
+}
+
+// newAppTestController is the controller creator used to return our internal testController
+func newAppTestController(request *http.Request) mvcapp.IController {
+	return &testController{
+		Controller: mvcapp.NewBaseController(request),
+	}
+}
+
+// TestApplication_Run ensures that the Application.Run method operates as expected
+func TestApplication_Run(t *testing.T) {
+	app := mvcapp.NewApplication()
+	app.Config.HTTPPort = 8906
+	app.RouteManager.RegisterController("Home", newAppTestController)
+
+	if app == nil || app.Config.HTTPPort != 8906 {
+		t.Fatal("Failed to create mvcapp Application object")
+	}
+
+	go func() {
+		app.Run()
+	}()
+
+	res, err := http.Get("http://localhost:8906")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+	defer app.Stop()
+
+	data, err := ioutil.ReadAll(res.Body)
+	actualBody := string(data)
+
+	if len(actualBody) < 50 {
+		t.Error("Did not receive expected 404 result")
+		t.Log(actualBody)
+	}
+
+	if err := app.Run(); err == nil {
+		t.Error("Failed to block & return error when HTTPServer is clearly in use")
+	}
+}
+
+// TestApplication_RedirectSecure ensures that the Application.RedirectSecure method redirects to https
+// while allowing ACME HTTP-01 challenges through
+func TestApplication_RedirectSecure(t *testing.T) {
+	app := mvcapp.NewApplication()
+
+	req := httptest.NewRequest("GET", "http://localhost/.well-known/acme-challenge/token", nil)
+	recorder := httptest.NewRecorder()
+	app.RedirectSecure(recorder, req)
+	if recorder.Code != http.StatusTemporaryRedirect {
+		t.Errorf("Failed to redirect without an ACME manager, received status %d", recorder.Code)
+	}
+
+	app.ACMEManager = mvcapp.NewACMEManager()
+	recorder = httptest.NewRecorder()
+	app.RedirectSecure(recorder, req)
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("Failed to pass challenge request to the ACME manager, received status %d", recorder.Code)
+	}
+}
+
+// TestApplication_SecureURL ensures that the Application.SecureURL method returns the expected values
+// for the port, canonical domain and www policy configurations
+func TestApplication_SecureURL(t *testing.T) {
+	app := mvcapp.NewApplication()
+	app.Config.HTTPSPort = 443
+
+	req := httptest.NewRequest("GET", "http://example.com:8080/home/index?a=1&b=2", nil)
+	if target := app.SecureURL(req); target != "https://example.com/home/index?a=1&b=2" {
+		t.Errorf("Failed to drop the http port from the target: %s", target)
+	}
+
+	app.Config.HTTPSPort = 8443
+	if target := app.SecureURL(req); target != "https://example.com:8443/home/index?a=1&b=2" {
+		t.Errorf("Failed to use the configured HTTPSPort: %s", target)
+	}
+
+	app.Config.HTTPSPort = 443
+	app.Config.CanonicalRedirect = true
+	app.Config.DomainName = "canonical.com"
+	app.Config.WWWPolicy = "www"
+	if target := app.SecureURL(req); target != "https://www.canonical.com/home/index?a=1&b=2" {
+		t.Errorf("Failed to apply the canonical domain and www policy: %s", target)
+	}
+
+	app.Config.CanonicalRedirect = false
+	app.Config.WWWPolicy = "non-www"
+	req = httptest.NewRequest("GET", "http://www.example.com/", nil)
+	if target := app.SecureURL(req); target != "https://example.com/" {
+		t.Errorf("Failed to apply the non-www policy: %s", target)
+	}
+
+	app.Config.HTTPSRedirectStatus = 308
+	recorder := httptest.NewRecorder()
+	app.RedirectSecure(recorder, req)
+	if recorder.Code != http.StatusPermanentRedirect || recorder.Header().Get("Location") != "https://example.com/" {
+		t.Errorf("Failed to redirect with the configured status, received %d %s", recorder.Code, recorder.Header().Get("Location"))
+	}
+}
+
+// TestApplication_RedirectSecureJS ensures that the Application.RedirectSecureJS method keeps the query
+// string and escapes the target url
+func TestApplication_RedirectSecureJS(t *testing.T) {
+	app := mvcapp.NewApplication()
+	app.Config.HTTPSPort = 443
+
+	req := httptest.NewRequest("GET", "http://example.com/home?q=1", nil)
+	req.Host = "example.com';alert(1);'"
+	recorder := httptest.NewRecorder()
+	app.RedirectSecureJS(recorder, req)
+
+	body := recorder.Body.String()
+	if strings.Contains(body, "example.com';alert(1)") {
+		t.Error("Failed to escape the requested host")
+	}
+
+	if !strings.Contains(body, "/home?q") {
+		t.Error("Failed to keep the query string")
+	}
+}
+
+// TestApplication_HandleSecureRequest ensures that the Application.HandleSecureRequest method writes the
+// Strict-Transport-Security header and redirects to the canonical host
+func TestApplication_HandleSecureRequest(t *testing.T) {
+	app := mvcapp.NewApplication()
+	app.RouteManager.RegisterController("Home", newAppTestController)
+	app.Config.HSTSMaxAge = 31536000
+	app.Config.HSTSIncludeSubDomains = true
+	app.Config.HSTSPreload = true
+
+	req := httptest.NewRequest("GET", "https://example.com/", nil)
+	recorder := httptest.NewRecorder()
+	app.HandleSecureRequest(recorder, req)
+	if hsts := recorder.Header().Get("Strict-Transport-Security"); hsts != "max-age=31536000; includeSubDomains; preload" {
+		t.Errorf("Failed to write the expected HSTS header: %s", hsts)
+	}
+
+	req = httptest.NewRequest("GET", "http://example.com/", nil)
+	recorder = httptest.NewRecorder()
+	app.HandleSecureRequest(recorder, req)
+	if hsts := recorder.Header().Get("Strict-Transport-Security"); hsts != "" {
+		t.Error("Failed to prevent writing the HSTS header to a plain http response")
+	}
+
+	app.Config.WWWPolicy = "www"
+	req = httptest.NewRequest("GET", "https://example.com/home?q=1", nil)
+	recorder = httptest.NewRecorder()
+	app.HandleSecureRequest(recorder, req)
+	if recorder.Code != http.StatusTemporaryRedirect || recorder.Header().Get("Location") != "https://www.example.com/home?q=1" {
+		t.Errorf("Failed to redirect to the canonical host, received %d %s", recorder.Code, recorder.Header().Get("Location"))
+	}
+}
+
+// TestApplication_RedirectSecureProxy ensures that the Application.RedirectSecure method serves requests
+// that a trusted proxy received over https rather than redirecting them forever
+func TestApplication_RedirectSecureProxy(t *testing.T) {
+	config := mvcapp.NewConfigurationManager()
+	config.TrustedProxies = []string{"127.0.0.1"}
+	app := mvcapp.NewApplicationFromConfig(config)
+	app.RouteManager.RegisterController("Home", newAppTestController)
+
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.RemoteAddr = "127.0.0.1:5000"
+	recorder := httptest.NewRecorder()
+	app.RedirectSecure(recorder, req)
+	if recorder.Code != http.StatusTemporaryRedirect {
+		t.Errorf("Failed to redirect a plain http request, received status %d", recorder.Code)
+	}
+
+	req.Header.Set("X-Forwarded-Proto", "https")
+	recorder = httptest.NewRecorder()
+	app.RedirectSecure(recorder, req)
+	if recorder.Code == http.StatusTemporaryRedirect {
+		t.Error("Failed to serve a request received over https by a trusted proxy")
+	}
+
+	// The https port of the application isn't the public port of the proxy
+	app.Config.HTTPSPort = 8443
+	if target := app.SecureURL(req); target != "https://example.com/" {
+		t.Errorf("Failed to omit the port of a request received over https by a trusted proxy: %s", target)
+	}
+
+	// Plain http listeners (see Run) behind the proxy write the HSTS header and redirect to the
+	// canonical host
+	app.Config.HSTSMaxAge = 600
+	app.Config.WWWPolicy = "www"
+	handler := app.ListenerHandler(mvcapp.NewListener("plain", mvcapp.ListenerNetworkTCP, "127.0.0.1:0"))
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Header().Get("Strict-Transport-Security") != "max-age=600" || recorder.Header().Get("Location") != "https://www.example.com/" {
+		t.Errorf("Failed to handle the proxied https request securely, received %d %v", recorder.Code, recorder.Header())
+	}
+
+	req.Header.Del("X-Forwarded-Proto")
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	if recorder.Header().Get("Strict-Transport-Security") != "" || recorder.Code == http.StatusTemporaryRedirect {
+		t.Errorf("Unexpected handling of a plain http request, received %d %v", recorder.Code, recorder.Header())
+	}
+}
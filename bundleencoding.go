@@ -0,0 +1,113 @@
+/*
+	Digivance MVC Application Framework
+	Bundle Encoding Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the pre-compressed content bundle variants. Compressed copies of each bundle are
+	written at build time at the maximum compression level, and the variant served is negotiated
+	from the Accept-Encoding request header, so bundles are never compressed per request.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// BundleEncoding defines a content coding that bundles are pre-compressed with
+type BundleEncoding struct {
+	// Name is the content coding as used in the Accept-Encoding and Content-Encoding headers
+	Name string
+
+	// Extension is appended to the bundle filename for the compressed variant (E.g. .gz)
+	Extension string
+
+	// NewWriter returns a writer that compresses to the provided writer at maximum compression
+	NewWriter func(io.Writer) (io.WriteCloser, error)
+}
+
+// DefaultBundleEncodings returns the content codings supported by the standard library, in
+// order of server preference
+func DefaultBundleEncodings() []*BundleEncoding {
+	return []*BundleEncoding{
+		{
+			Name:      "gzip",
+			Extension: ".gz",
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return gzip.NewWriterLevel(w, gzip.BestCompression)
+			},
+		},
+		{
+			// The http deflate content coding is the zlib format (RFC 1950), not raw deflate
+			Name:      "deflate",
+			Extension: ".zz",
+			NewWriter: func(w io.Writer) (io.WriteCloser, error) {
+				return zlib.NewWriterLevel(w, flate.BestCompression)
+			},
+		},
+	}
+}
+
+// Compress returns the provided data compressed with this encoding
+func (encoding *BundleEncoding) Compress(data []byte) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	writer, err := encoding.NewWriter(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := writer.Write(data); err != nil {
+		writer.Close()
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// NegotiateEncoding returns the first of the available encodings (in server preference order)
+// that is accepted by the provided Accept-Encoding header value, or nil for the identity encoding
+func NegotiateEncoding(acceptEncoding string, available []*BundleEncoding) *BundleEncoding {
+	accepted := make(map[string]float64, 0)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		fields := strings.Split(part, ";")
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = value
+				}
+			}
+		}
+
+		accepted[name] = quality
+	}
+
+	for _, encoding := range available {
+		quality, ok := accepted[encoding.Name]
+		if !ok {
+			quality, ok = accepted["*"]
+		}
+
+		if ok && quality > 0 {
+			return encoding
+		}
+	}
+
+	return nil
+}
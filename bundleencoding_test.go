@@ -0,0 +1,106 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Bundle Encoding Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of bundleencoding.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in bundleencoding.go
+*/
+
+package mvcapp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestNegotiateEncoding ensures that the NegotiateEncoding method honors the Accept-Encoding header
+func TestNegotiateEncoding(t *testing.T) {
+	encodings := mvcapp.DefaultBundleEncodings()
+	tests := map[string]string{
+		"":                        "",
+		"gzip, deflate, br":       "gzip",
+		"deflate":                 "deflate",
+		"gzip;q=0, deflate;q=0.5": "deflate",
+		"br":                      "",
+		"*":                       "gzip",
+		"*, gzip;q=0":             "deflate",
+	}
+
+	for header, expected := range tests {
+		name := ""
+		if encoding := mvcapp.NegotiateEncoding(header, encodings); encoding != nil {
+			name = encoding.Name
+		}
+
+		if name != expected {
+			t.Errorf("Failed to negotiate %q, expected %q received %q", header, expected, name)
+		}
+	}
+}
+
+// TestBundleManager_ServeEncodedBundle ensures that pre-compressed bundle variants are written and served
+func TestBundleManager_ServeEncodedBundle(t *testing.T) {
+	filename := fmt.Sprintf("%s/encoded.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write encoded.css: %s", err)
+	}
+	defer os.RemoveAll(filename)
+	defer os.RemoveAll("bundle")
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("site.css", "text/css", []string{filename}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	url, err := bundleManager.BundleURL("site.css")
+	if err != nil {
+		t.Fatalf("Failed to build bundle: %s", err)
+	}
+
+	request := httptest.NewRequest("GET", url, nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	response := httptest.NewRecorder()
+	bundleManager.ServeBundle(response, request)
+
+	if response.Header().Get("Content-Encoding") != "gzip" || response.Header().Get("Vary") != "Accept-Encoding" {
+		t.Fatalf("Failed to serve gzip variant: %v", response.Header())
+	}
+
+	reader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		t.Fatalf("Failed to read gzip variant: %s", err)
+	}
+
+	if data, _ := ioutil.ReadAll(reader); string(data) != "a{color:blue}\n/*# sourceMappingURL=site.css.map */" {
+		t.Errorf("Failed to serve gzip content: %s", data)
+	}
+
+	request = httptest.NewRequest("GET", url, nil)
+	request.Header.Set("Accept-Encoding", "deflate")
+	response = httptest.NewRecorder()
+	bundleManager.ServeBundle(response, request)
+
+	zreader, err := zlib.NewReader(bytes.NewReader(response.Body.Bytes()))
+	if err != nil || response.Header().Get("Content-Encoding") != "deflate" {
+		t.Fatalf("Failed to serve deflate variant: %v", err)
+	}
+
+	if data, _ := ioutil.ReadAll(zreader); string(data) != "a{color:blue}\n/*# sourceMappingURL=site.css.map */" {
+		t.Errorf("Failed to serve deflate content: %s", data)
+	}
+
+	response = httptest.NewRecorder()
+	bundleManager.ServeBundle(response, httptest.NewRequest("GET", url, nil))
+	if response.Header().Get("Content-Encoding") != "" || response.Body.String() != "a{color:blue}\n/*# sourceMappingURL=site.css.map */" {
+		t.Errorf("Failed to serve identity variant: %s", response.Body.String())
+	}
+}
@@ -1,195 +1,982 @@
-/*
-	Digivance MVC Application Framework
-	Content Bundle Manager
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines the content bundle manager object for the Digivance mvcapp package
-*/
-
-package mvcapp
-
-import (
-	"bufio"
-	"bytes"
-	"errors"
-	"fmt"
-	"io/ioutil"
-	"os"
-	"strings"
-	"time"
-
-	"github.com/tdewolff/minify"
-	"github.com/tdewolff/minify/css"
-	"github.com/tdewolff/minify/js"
-)
-
-// BundleManager is an object that we register our content bundles with and is used
-// to compile said content bundles.
-type BundleManager struct {
-	// Bundles are a collection of filenames that are compiled into a single deliverable
-	Bundles  map[string]*BundleMap
-	Minifier *minify.M
-}
-
-// NewBundleManager returns a new instance of the bundle manager object
-func NewBundleManager() *BundleManager {
-	rtn := &BundleManager{
-		Bundles:  make(map[string]*BundleMap, 0),
-		Minifier: minify.New(),
-	}
-
-	rtn.Minifier.AddFunc("text/css", css.Minify)
-	rtn.Minifier.AddFunc("text/javascript", js.Minify)
-
-	return rtn
-}
-
-// CreateBundle is used to register a bundle by name, mime type and slice of filenames
-// Once registered, bundles can be compiled using the BuildBundle method referencing
-// the provided bundleName
-func (bundleManager *BundleManager) CreateBundle(bundleName string, mimeType string, bundledFiles []string) error {
-	if bundleManager.Bundles[bundleName] != nil {
-		return errors.New("Failed to create new content bundle, there is already a bundle created using this name")
-	}
-
-	bundleManager.Bundles[bundleName] = NewBundleMap(mimeType, bundledFiles)
-	return nil
-}
-
-// RemoveBundle is used to remove a bundle from the manager by name.
-func (bundleManager *BundleManager) RemoveBundle(bundleName string) error {
-	if bundleManager.Bundles[bundleName] == nil {
-		return fmt.Errorf("Failed to remove bundle, no bundles found for %s", bundleName)
-	}
-
-	delete(bundleManager.Bundles, bundleName)
-	return nil
-}
-
-// doBuild is really just a micro-optimization, it can be used so that "ALL" methods
-// of this object only have to iterate the bundles map once
-func (bundleManager *BundleManager) doBuild(bundleMap *BundleMap, bundleName string) error {
-	if bundleMap == nil {
-		return errors.New("Failed to build bundle, none found for provided name")
-	}
-
-	if len(bundleMap.Files) <= 0 {
-		return errors.New("Failed to build bundle, no files registered")
-	}
-
-	bundlePath := fmt.Sprintf("%s/bundle", GetApplicationPath())
-	os.Mkdir(bundlePath, 0644)
-	data := []byte{}
-
-	for _, filename := range bundleMap.Files {
-		if !strings.HasPrefix(filename, GetApplicationPath()) {
-			// Is tested successfully, hard to demonstrate because of scoping when testing
-			// (e.g. the GetApplicationPath is different between the unit test and the lib)
-			filename = fmt.Sprintf("%s/%s", GetApplicationPath(), filename)
-		}
-
-		contentData, err := ioutil.ReadFile(filename)
-		if err != nil {
-			return fmt.Errorf("Failed to bundle %s : %s", filename, err)
-		}
-
-		data = append(data, contentData...)
-	}
-
-	bundleFilename := fmt.Sprintf("%s/bundle/%s", GetApplicationPath(), bundleName)
-	os.RemoveAll(bundleFilename)
-
-	bundleFile, err := os.Create(bundleFilename)
-	if err != nil {
-		// this would be a permissions error, can't test
-		return fmt.Errorf("Failed to create new bundle file: %s", err)
-	}
-
-	reader := bytes.NewReader(data)
-	writer := bufio.NewWriter(bundleFile)
-	defer func() {
-		writer.Flush()
-		bundleFile.Close()
-	}()
-
-	if err := bundleManager.Minifier.Minify(bundleMap.MimeType, writer, reader); err != nil {
-		// Syntax error maybe?
-		return fmt.Errorf("Failed to minify and write the bundle file: %s", err)
-	}
-
-	bundleMap.BuildDate = time.Now()
-	return nil
-}
-
-// BuildBundle is used to compile the registered bundle defined by bundleName.
-// This method will delete the existing bundle file if it exists and replacing
-// with a newly built copy
-func (bundleManager *BundleManager) BuildBundle(bundleName string) error {
-	bundleMap := bundleManager.Bundles[bundleName]
-	return bundleManager.doBuild(bundleMap, bundleName)
-}
-
-// BuildAllBundles is used to build all of the currently registered content bundles
-func (bundleManager *BundleManager) BuildAllBundles() error {
-	for buildName, buildMap := range bundleManager.Bundles {
-		if err := bundleManager.doBuild(buildMap, buildName); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// RebuildBundle is used to compare the last modified times of the files in a content
-// bundle to the creation time of this content bundle, if files have been modified
-// since this bundle was built it will be built a new. Returns nil if no need to build
-func (bundleManager *BundleManager) RebuildBundle(bundleName string) error {
-	bundleMap := bundleManager.Bundles[bundleName]
-
-	if bundleMap.BuildDate.IsZero() {
-		return bundleManager.doBuild(bundleMap, bundleName)
-	}
-
-	for _, filename := range bundleMap.Files {
-		si, err := os.Stat(filename)
-		if err != nil {
-			return err
-		}
-
-		if si.ModTime().After(bundleMap.BuildDate) {
-			return bundleManager.doBuild(bundleMap, bundleName)
-		}
-	}
-
-	return nil
-}
-
-// RebuildAllBundles is used to loop through all of the registered content bundles
-// and compare file modification dates to the build time of the bundle. If files
-// have been modified since this bundle was built, it will be built a new
-func (bundleManager *BundleManager) RebuildAllBundles() error {
-	for bundleName, bundleMap := range bundleManager.Bundles {
-		if bundleMap.BuildDate.IsZero() {
-			if err := bundleManager.doBuild(bundleMap, bundleName); err != nil {
-				return err
-			}
-		}
-
-		for _, filename := range bundleMap.Files {
-			si, err := os.Stat(filename)
-			if err != nil {
-				return err
-			}
-
-			if si.ModTime().After(bundleMap.BuildDate) {
-				if err := bundleManager.doBuild(bundleMap, bundleName); err != nil {
-					return err
-				}
-
-				break
-			}
-		}
-	}
-
-	return nil
-}
+/*
+	Digivance MVC Application Framework
+	Content Bundle Manager
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the content bundle manager object for the Digivance mvcapp package
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tdewolff/minify"
+	"github.com/tdewolff/minify/css"
+	"github.com/tdewolff/minify/js"
+)
+
+// BundleManager is an object that we register our content bundles with and is used
+// to compile said content bundles.
+type BundleManager struct {
+	// Bundles are a collection of filenames that are compiled into a single deliverable
+	Bundles  map[string]*BundleMap
+	Minifier *minify.M
+
+	// URLPrefix is the url path that bundles are served from by the route manager. Bundle
+	// urls take the form URLPrefix/fingerprint/bundleName
+	URLPrefix string
+
+	// Encodings are the content codings that bundles are pre-compressed with when built, the
+	// served variant is negotiated from the Accept-Encoding request header
+	Encodings []*BundleEncoding
+
+	// MaxParallelBuilds is the number of bundles BuildAllBundles and RebuildAllBundles build at
+	// once (defaults to the number of CPUs)
+	MaxParallelBuilds int
+
+	// InMemory keeps built bundles in memory rather than writing them to the bundle folder,
+	// for read only file systems and containers
+	InMemory bool
+
+	// DevelopmentMode renders one tag per (unminified) source file of a bundle rather than the
+	// single built bundle, and rebuilds bundles on request when a source file has changed
+	DevelopmentMode bool
+
+	// ManifestFilename is the full path and filename of the bundle manifest that was loaded
+	// (see LoadManifest), blank if none
+	ManifestFilename string
+
+	// buildMutex prevents concurrent requests from building the same bundles at once, it also
+	// guards Bundles and the manifest state against the manifest watcher
+	buildMutex sync.Mutex
+
+	// contentMutex protects the build results of the bundle maps while they are replaced
+	contentMutex sync.RWMutex
+
+	// definedBundles is the set of bundle names registered by ApplyBundleDefinitions
+	definedBundles map[string]bool
+
+	// manifestModTime is the modification time of the manifest when it was loaded
+	manifestModTime time.Time
+
+	// manifestStop is closed to stop the WatchManifest goroutine
+	manifestStop chan struct{}
+}
+
+// NewBundleManager returns a new instance of the bundle manager object
+func NewBundleManager() *BundleManager {
+	rtn := &BundleManager{
+		Bundles:           make(map[string]*BundleMap, 0),
+		Minifier:          minify.New(),
+		URLPrefix:         "/bundle/",
+		Encodings:         DefaultBundleEncodings(),
+		MaxParallelBuilds: runtime.NumCPU(),
+		definedBundles:    make(map[string]bool, 0),
+	}
+
+	rtn.Minifier.AddFunc("text/css", css.Minify)
+	rtn.Minifier.AddFunc("text/javascript", js.Minify)
+
+	return rtn
+}
+
+// NewBundleManagerFromConfig returns a new instance of the bundle manager object with members
+// populated from the provided configuration manager object. Bundles are registered from the
+// Bundles section of the configuration and from the BundleManifest file (if it exists)
+func NewBundleManagerFromConfig(config *ConfigurationManager) *BundleManager {
+	rtn := NewBundleManager()
+	rtn.DevelopmentMode = config.BundleDevelopmentMode || config.DevelopmentMode
+	rtn.InMemory = config.BundleInMemory
+	if config.BundleMaxParallelBuilds > 0 {
+		rtn.MaxParallelBuilds = config.BundleMaxParallelBuilds
+	}
+
+	if len(config.Bundles) > 0 {
+		if err := rtn.ApplyBundleDefinitions(config.Bundles); err != nil {
+			LogErrorf("Failed to load bundles from configuration: %s", err)
+		}
+
+		// Bundles from the configuration are kept when the manifest is (re)loaded
+		rtn.definedBundles = make(map[string]bool, 0)
+	}
+
+	if config.BundleManifest != "" {
+		if err := rtn.LoadManifest(config.BundleManifest); err != nil && !os.IsNotExist(err) {
+			LogErrorf("Failed to load bundle manifest: %s", err)
+		}
+	}
+
+	return rtn
+}
+
+// getBundle returns the named bundle map, safe to call while the manifest is reloaded
+func (bundleManager *BundleManager) getBundle(bundleName string) *BundleMap {
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	return bundleManager.Bundles[bundleName]
+}
+
+// bundleOutputs returns the full path and filenames that the registered bundles are built to,
+// including their source maps and compressed variants, so that glob patterns don't bundle them
+// again. The buildMutex must be held by the caller
+func (bundleManager *BundleManager) bundleOutputs() map[string]bool {
+	rtn := make(map[string]bool, 0)
+	for name, bundleMap := range bundleManager.Bundles {
+		filename := filepath.Clean(bundleMap.OutputFilename(name))
+		rtn[filename] = true
+		rtn[filename+".map"] = true
+		for _, encoding := range bundleManager.Encodings {
+			rtn[filename+encoding.Extension] = true
+		}
+	}
+
+	return rtn
+}
+
+// resolveFiles returns the files of the provided bundle map (see BundleMap.ResolveFiles) without
+// the outputs of the registered bundles
+func (bundleManager *BundleManager) resolveFiles(bundleMap *BundleMap) ([]string, error) {
+	bundleManager.buildMutex.Lock()
+	outputs := bundleManager.bundleOutputs()
+	bundleManager.buildMutex.Unlock()
+
+	return bundleMap.resolveFiles(outputs)
+}
+
+// CreateBundle is used to register a bundle by name, mime type and slice of filenames
+// Once registered, bundles can be compiled using the BuildBundle method referencing
+// the provided bundleName
+func (bundleManager *BundleManager) CreateBundle(bundleName string, mimeType string, bundledFiles []string) error {
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	if bundleManager.Bundles[bundleName] != nil {
+		return errors.New("Failed to create new content bundle, there is already a bundle created using this name")
+	}
+
+	bundleManager.Bundles[bundleName] = NewBundleMap(mimeType, bundledFiles)
+	return nil
+}
+
+// RemoveBundle is used to remove a bundle from the manager by name.
+func (bundleManager *BundleManager) RemoveBundle(bundleName string) error {
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	if bundleManager.Bundles[bundleName] == nil {
+		return fmt.Errorf("Failed to remove bundle, no bundles found for %s", bundleName)
+	}
+
+	delete(bundleManager.Bundles, bundleName)
+	return nil
+}
+
+// doBuild is really just a micro-optimization, it can be used so that "ALL" methods
+// of this object only have to iterate the bundles map once
+func (bundleManager *BundleManager) doBuild(bundleMap *BundleMap, bundleName string, outputs map[string]bool) error {
+	if bundleMap == nil {
+		return errors.New("Failed to build bundle, none found for provided name")
+	}
+
+	if len(bundleMap.Files) <= 0 {
+		return errors.New("Failed to build bundle, no files registered")
+	}
+
+	files, err := bundleMap.resolveFiles(outputs)
+	if err != nil {
+		return err
+	}
+
+	if len(files) <= 0 {
+		return errors.New("Failed to build bundle, no files matched")
+	}
+
+	mimeType, params, err := mime.ParseMediaType(bundleMap.MimeType)
+	if err != nil {
+		return fmt.Errorf("Failed to parse bundle mime type: %s", err)
+	}
+
+	for key, value := range bundleMap.MinifyParams {
+		params[key] = value
+	}
+
+	// Each file is minified on its own so the source map knows where every file starts, the
+	// tokens of the minified output are then mapped back to their original positions
+	isCSS := strings.Contains(mimeType, "css")
+	sourceMap := NewSourceMap(filepath.Base(bundleName))
+	output := new(bytes.Buffer)
+
+	for _, filename := range files {
+		contentData, err := readFile(filename)
+		if err != nil {
+			return fmt.Errorf("Failed to bundle %s : %s", filename, err)
+		}
+
+		if isCSS {
+			contentData = rewriteCSSURLs(contentData, filename, bundleManager.bundleURLDepth(bundleName, false))
+		}
+
+		line, column := generatedPosition(output.Bytes())
+		source := sourceMap.AddSource(relativeBundlePath(filename), string(contentData))
+
+		if bundleMap.Minify {
+			minified := new(bytes.Buffer)
+			if err := bundleManager.Minifier.MinifyMimetype([]byte(mimeType), minified, bytes.NewReader(contentData), params); err != nil {
+				// Syntax error maybe?
+				return fmt.Errorf("Failed to minify %s : %s", filename, err)
+			}
+
+			sourceMap.AddMapping(line, column, source, 0, 0)
+			sourceMap.AddTokenMappings(minified.Bytes(), line, column, source, contentData)
+			output.Write(minified.Bytes())
+			if !isCSS {
+				output.WriteString(";\n")
+			}
+		} else {
+			output.Write(contentData)
+			for i := 0; i < sourceLines(string(contentData)); i++ {
+				sourceMap.AddMapping(line+i, column, source, i, 0)
+				column = 0
+			}
+
+			if len(contentData) > 0 && contentData[len(contentData)-1] != '\n' {
+				output.WriteString("\n")
+			}
+		}
+	}
+
+	// The source map is referenced by a comment at the end of the bundle, for tools that don't
+	// read the SourceMap header. The comment is part of the hashed content
+	if bundleMap.SourceMap {
+		if output.Len() > 0 && output.Bytes()[output.Len()-1] != '\n' {
+			output.WriteString("\n")
+		}
+
+		if isCSS {
+			fmt.Fprintf(output, "/*# sourceMappingURL=%s.map */", filepath.Base(bundleName))
+		} else {
+			fmt.Fprintf(output, "//# sourceMappingURL=%s.map", filepath.Base(bundleName))
+		}
+	}
+
+	// artifacts are the built files keyed by the suffix appended to the bundle filename
+	artifacts := map[string][]byte{"": output.Bytes()}
+	for _, encoding := range bundleManager.Encodings {
+		compressed, err := encoding.Compress(output.Bytes())
+		if err != nil {
+			return fmt.Errorf("Failed to %s compress the bundle file: %s", encoding.Name, err)
+		}
+
+		artifacts[encoding.Extension] = compressed
+	}
+
+	if bundleMap.SourceMap {
+		sourceMapData, err := json.Marshal(sourceMap)
+		if err != nil {
+			return fmt.Errorf("Failed to create the bundle source map: %s", err)
+		}
+
+		artifacts[".map"] = sourceMapData
+	}
+
+	if !bundleManager.InMemory {
+		bundleFilename := bundleMap.OutputFilename(bundleName)
+		if err := os.MkdirAll(filepath.Dir(bundleFilename), 0755); err != nil {
+			return fmt.Errorf("Failed to create the bundle folder: %s", err)
+		}
+
+		// The compressed variants and source map are written first, so the bundle file
+		// is never newer than its variants
+		for suffix, data := range artifacts {
+			if suffix == "" {
+				continue
+			}
+
+			if err := writeFileAtomic(bundleFilename+suffix, data); err != nil {
+				return fmt.Errorf("Failed to write the bundle file: %s", err)
+			}
+		}
+
+		if err := writeFileAtomic(bundleFilename, output.Bytes()); err != nil {
+			// this would be a permissions error, can't test
+			return fmt.Errorf("Failed to write the bundle file: %s", err)
+		}
+
+		if !bundleMap.SourceMap {
+			os.Remove(bundleFilename + ".map")
+		}
+
+		artifacts = nil
+	}
+
+	hash := sha256.Sum256(output.Bytes())
+	integrity := sha512.Sum384(output.Bytes())
+
+	bundleManager.contentMutex.Lock()
+	defer bundleManager.contentMutex.Unlock()
+
+	bundleMap.artifacts = artifacts
+	bundleMap.Hash = hex.EncodeToString(hash[:])
+	bundleMap.Integrity = "sha384-" + base64.StdEncoding.EncodeToString(integrity[:])
+	bundleMap.SourceFiles = files
+	bundleMap.BuildDate = time.Now()
+	return nil
+}
+
+// writeFileAtomic writes the provided data to a temporary file in the same folder and renames
+// it over filename, so readers see either the previous or the new content but never a partial file
+func writeFileAtomic(filename string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp")
+	if err != nil {
+		return err
+	}
+
+	tempFilename := file.Name()
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tempFilename)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(tempFilename)
+		return err
+	}
+
+	if err := os.Chmod(tempFilename, 0644); err != nil {
+		os.Remove(tempFilename)
+		return err
+	}
+
+	if err := os.Rename(tempFilename, filename); err != nil {
+		os.Remove(tempFilename)
+		return err
+	}
+
+	return nil
+}
+
+// readArtifact returns the built bundle file with the provided suffix (E.g. .gz or .map) from
+// memory or from the bundle folder
+func (bundleManager *BundleManager) readArtifact(bundleMap *BundleMap, bundleName string, suffix string) ([]byte, error) {
+	if bundleManager.InMemory {
+		bundleManager.contentMutex.RLock()
+		defer bundleManager.contentMutex.RUnlock()
+
+		if data, ok := bundleMap.artifacts[suffix]; ok {
+			return data, nil
+		}
+
+		return nil, os.ErrNotExist
+	}
+
+	return ioutil.ReadFile(bundleMap.OutputFilename(bundleName) + suffix)
+}
+
+// bundleSourcePath returns the full path of a bundled source file, relative filenames are
+// resolved from the application path
+func bundleSourcePath(filename string) string {
+	if !strings.HasPrefix(filename, GetApplicationPath()) {
+		// Is tested successfully, hard to demonstrate because of scoping when testing
+		// (e.g. the GetApplicationPath is different between the unit test and the lib)
+		filename = fmt.Sprintf("%s/%s", GetApplicationPath(), filename)
+	}
+
+	return filename
+}
+
+// cssURLPattern matches the url(...) references of css content
+var cssURLPattern = regexp.MustCompile(`url\(\s*(['"]?)([^'")]+)(['"]?)\s*\)`)
+
+// rewriteCSSURLs rewrites the relative url(...) references of the provided css source file so
+// they resolve from a bundle served depth folders below the site root. Absolute urls, data uris
+// and references outside of the application path are left as is
+func rewriteCSSURLs(content []byte, filename string, depth int) []byte {
+	folder := path.Dir(relativeBundlePath(filename))
+
+	return cssURLPattern.ReplaceAllFunc(content, func(match []byte) []byte {
+		parts := cssURLPattern.FindSubmatch(match)
+		url := strings.TrimSpace(string(parts[2]))
+		lower := strings.ToLower(url)
+
+		if url == "" || strings.HasPrefix(url, "/") || strings.HasPrefix(url, "#") ||
+			strings.HasPrefix(lower, "data:") || strings.Contains(url, ":") {
+			return match
+		}
+
+		suffix := ""
+		if index := strings.IndexAny(url, "?#"); index >= 0 {
+			url, suffix = url[:index], url[index:]
+		}
+
+		resolved := path.Join(folder, url)
+		if resolved == ".." || strings.HasPrefix(resolved, "../") {
+			return match
+		}
+
+		rewritten := strings.Repeat("../", depth) + resolved + suffix
+		return []byte(fmt.Sprintf("url(%s%s%s)", parts[1], rewritten, parts[3]))
+	})
+}
+
+// bundleURLDepth returns the number of folders between the site root and the url that the named
+// bundle (or in development mode, its source files) are served from
+func (bundleManager *BundleManager) bundleURLDepth(bundleName string, source bool) int {
+	depth := strings.Count(bundleName, "/") + 1
+	if source {
+		depth++
+	}
+
+	if prefix := strings.Trim(bundleManager.URLPrefix, "/"); prefix != "" {
+		depth += strings.Count(prefix, "/") + 1
+	}
+
+	return depth
+}
+
+// BundleFilename returns the full path and filename that the named bundle is built to
+func (bundleManager *BundleManager) BundleFilename(bundleName string) string {
+	if bundleMap := bundleManager.getBundle(bundleName); bundleMap != nil {
+		return bundleMap.OutputFilename(bundleName)
+	}
+
+	return fmt.Sprintf("%s/bundle/%s", GetApplicationPath(), bundleName)
+}
+
+// snapshot returns a copy of the provided bundle map taken while its build results can't be
+// replaced, so that the hash, integrity, build date and artifacts read from it are consistent
+func (bundleManager *BundleManager) snapshot(bundleMap *BundleMap) *BundleMap {
+	bundleManager.contentMutex.RLock()
+	defer bundleManager.contentMutex.RUnlock()
+
+	rtn := *bundleMap
+	return &rtn
+}
+
+// ensureBuilt builds the named bundle if it has not been built yet, or (in development mode)
+// if any of its source files have changed since it was built. Returns a snapshot of the built
+// bundle map (see snapshot) that is safe to read while the bundle is rebuilt
+func (bundleManager *BundleManager) ensureBuilt(bundleName string) (*BundleMap, error) {
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	bundleMap := bundleManager.Bundles[bundleName]
+	if bundleMap == nil {
+		return nil, fmt.Errorf("No bundle found named: %s", bundleName)
+	}
+
+	if bundleManager.DevelopmentMode {
+		if err := bundleManager.rebuild(bundleMap, bundleName, bundleManager.bundleOutputs()); err != nil {
+			return nil, err
+		}
+	} else if bundleManager.snapshot(bundleMap).BuildDate.IsZero() {
+		if err := bundleManager.doBuild(bundleMap, bundleName, bundleManager.bundleOutputs()); err != nil {
+			return nil, err
+		}
+	}
+
+	return bundleManager.snapshot(bundleMap), nil
+}
+
+// BundleURL returns the fingerprinted url of the named bundle, building the bundle if needed
+func (bundleManager *BundleManager) BundleURL(bundleName string) (string, error) {
+	bundleMap, err := bundleManager.ensureBuilt(bundleName)
+	if err != nil {
+		return "", err
+	}
+
+	return bundleManager.bundleURL(bundleMap, bundleName), nil
+}
+
+// bundleURL returns the fingerprinted url of the provided built bundle map
+func (bundleManager *BundleManager) bundleURL(bundleMap *BundleMap, bundleName string) string {
+	return fmt.Sprintf("%s/%s/%s", strings.TrimRight(bundleManager.URLPrefix, "/"), bundleMap.Fingerprint(), bundleName)
+}
+
+// SourceURLs returns the urls of the individual source files of the named bundle, as served in
+// development mode. Each url carries the modification time of the file to bust browser caches
+func (bundleManager *BundleManager) SourceURLs(bundleName string) ([]string, error) {
+	bundleMap := bundleManager.getBundle(bundleName)
+	if bundleMap == nil {
+		return nil, fmt.Errorf("No bundle found named: %s", bundleName)
+	}
+
+	files, err := bundleManager.resolveFiles(bundleMap)
+	if err != nil {
+		return nil, err
+	}
+
+	rtn := []string{}
+	for i, filename := range files {
+		si, err := statFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to bundle %s : %s", filename, err)
+		}
+
+		rtn = append(rtn, fmt.Sprintf("%s/dev/%s/%d?v=%d", strings.TrimRight(bundleManager.URLPrefix, "/"), bundleName, i, si.ModTime().Unix()))
+	}
+
+	return rtn, nil
+}
+
+// bundleTag returns the html <link> or <script> tag that includes the provided url, the
+// attributes (E.g. integrity) are appended to the tag as is
+func bundleTag(mimeType string, url string, attributes string) (string, error) {
+	switch {
+	case strings.Contains(mimeType, "css"):
+		return fmt.Sprintf("<link rel=\"stylesheet\" href=\"%s\"%s />", template.HTMLEscapeString(url), attributes), nil
+	case strings.Contains(mimeType, "javascript"):
+		return fmt.Sprintf("<script type=\"text/javascript\" src=\"%s\"%s></script>", template.HTMLEscapeString(url), attributes), nil
+	}
+
+	return "", fmt.Errorf("Failed to create tag, unsupported mime type: %s", mimeType)
+}
+
+// integrityAttribute returns the integrity html attribute of the provided SRI hash
+func integrityAttribute(integrity string) string {
+	if integrity == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" integrity=\"%s\"", template.HTMLEscapeString(integrity))
+}
+
+// cdnBundleTag returns the tag that includes a bundle from its CDNURL, falling back to the local
+// url if the CDN copy fails to load or fails its integrity check. Stylesheets swap their href on
+// error, scripts flag the failure and a following inline script writes the local script tag so
+// that the script execution order is kept
+func cdnBundleTag(bundleName string, bundleMap *BundleMap, url string) (string, error) {
+	attributes := integrityAttribute(bundleMap.Integrity) + " crossorigin=\"anonymous\""
+
+	if strings.Contains(bundleMap.MimeType, "css") {
+		fallback := fmt.Sprintf("this.onerror=null;this.href='%s'", template.JSEscapeString(url))
+		return bundleTag(bundleMap.MimeType, bundleMap.CDNURL, fmt.Sprintf("%s onerror=\"%s\"", attributes, template.HTMLEscapeString(fallback)))
+	}
+
+	key := template.JSEscapeString(bundleName)
+	failed := fmt.Sprintf("(window.mvcappCDNFailed=window.mvcappCDNFailed||{})['%s']=true", key)
+	cdnTag, err := bundleTag(bundleMap.MimeType, bundleMap.CDNURL, fmt.Sprintf("%s onerror=\"%s\"", attributes, template.HTMLEscapeString(failed)))
+	if err != nil {
+		return "", err
+	}
+
+	localTag, err := bundleTag(bundleMap.MimeType, url, integrityAttribute(bundleMap.Integrity))
+	if err != nil {
+		return "", err
+	}
+
+	loader := fmt.Sprintf("<script type=\"text/javascript\">window.mvcappCDNFailed&&window.mvcappCDNFailed['%s']&&document.write('%s');</script>",
+		key, template.JSEscapeString(localTag))
+
+	return cdnTag + "\n" + loader, nil
+}
+
+// BundleIntegrity returns the SHA-384 subresource integrity hash of the named bundle, building
+// the bundle if needed. Used by the {{ BundleIntegrity "name" }} view function
+func (bundleManager *BundleManager) BundleIntegrity(bundleName string) (string, error) {
+	bundleMap, err := bundleManager.ensureBuilt(bundleName)
+	if err != nil {
+		return "", err
+	}
+
+	return bundleMap.Integrity, nil
+}
+
+// BundleTag returns the html <link> (for css) or <script> (for javascript) tag that includes
+// the named bundle at its current fingerprinted url with its integrity hash. Bundles with a
+// CDNURL are included from the CDN with a fallback to the local url. In development mode one
+// tag is returned per source file instead. Used by the {{ Bundle "name" }} view function
+func (bundleManager *BundleManager) BundleTag(bundleName string) (template.HTML, error) {
+	bundleMap := bundleManager.getBundle(bundleName)
+	if bundleMap == nil {
+		return "", fmt.Errorf("No bundle found named: %s", bundleName)
+	}
+
+	if bundleManager.DevelopmentMode {
+		sourceURLs, err := bundleManager.SourceURLs(bundleName)
+		if err != nil {
+			return "", err
+		}
+
+		tags := []string{}
+		for _, url := range sourceURLs {
+			tag, err := bundleTag(bundleMap.MimeType, url, "")
+			if err != nil {
+				return "", fmt.Errorf("Failed to create tag for bundle %s: %s", bundleName, err)
+			}
+
+			tags = append(tags, tag)
+		}
+
+		return template.HTML(strings.Join(tags, "\n")), nil
+	}
+
+	bundleMap, err := bundleManager.ensureBuilt(bundleName)
+	if err != nil {
+		return "", err
+	}
+
+	url := bundleManager.bundleURL(bundleMap, bundleName)
+	tag := ""
+	if bundleMap.CDNURL != "" {
+		tag, err = cdnBundleTag(bundleName, bundleMap, url)
+	} else {
+		tag, err = bundleTag(bundleMap.MimeType, url, integrityAttribute(bundleMap.Integrity))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("Failed to create tag for bundle %s: %s", bundleName, err)
+	}
+
+	return template.HTML(tag), nil
+}
+
+// serveSource serves a single unminified source file of a bundle (development mode only)
+func (bundleManager *BundleManager) serveSource(response http.ResponseWriter, request *http.Request, path string) bool {
+	index := strings.LastIndex(path, "/")
+	if index <= 0 {
+		return false
+	}
+
+	bundleMap := bundleManager.getBundle(path[:index])
+	fileIndex, err := strconv.Atoi(path[index+1:])
+	if bundleMap == nil || err != nil {
+		return false
+	}
+
+	files, err := bundleManager.resolveFiles(bundleMap)
+	if err != nil || fileIndex < 0 || fileIndex >= len(files) {
+		return false
+	}
+
+	filename := files[fileIndex]
+	si, err := statFile(filename)
+	if err != nil {
+		LogErrorf("Failed to read bundle source %s: %s", filename, err)
+		http.Error(response, "Failed to read bundle source", http.StatusNotFound)
+		return true
+	}
+
+	data, err := readFile(filename)
+	if err != nil {
+		LogErrorf("Failed to read bundle source %s: %s", filename, err)
+		http.Error(response, "Failed to read bundle source", http.StatusInternalServerError)
+		return true
+	}
+
+	if strings.Contains(bundleMap.MimeType, "css") {
+		data = rewriteCSSURLs(data, filename, bundleManager.bundleURLDepth(path[:index], true))
+	}
+
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("Content-Type", bundleMap.MimeType)
+	http.ServeContent(response, request, filename, si.ModTime(), bytes.NewReader(data))
+	return true
+}
+
+// ServeBundle serves requests made to the URLPrefix with the named bundles content. Requests
+// using the current fingerprint are cached forever (immutable), stale fingerprints receive the
+// current content but must be revalidated. Returns false if the request was not for a bundle
+func (bundleManager *BundleManager) ServeBundle(response http.ResponseWriter, request *http.Request) bool {
+	prefix := strings.TrimRight(bundleManager.URLPrefix, "/") + "/"
+	if !strings.HasPrefix(request.URL.Path, prefix) {
+		return false
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(request.URL.Path, prefix), "/", 2)
+	if len(parts) == 2 && parts[0] == "dev" && bundleManager.DevelopmentMode {
+		return bundleManager.serveSource(response, request, parts[1])
+	}
+
+	if len(parts) == 2 && strings.HasSuffix(parts[1], ".map") && bundleManager.getBundle(parts[1]) == nil {
+		return bundleManager.serveSourceMap(response, request, strings.TrimSuffix(parts[1], ".map"))
+	}
+
+	if len(parts) != 2 || bundleManager.getBundle(parts[1]) == nil {
+		return false
+	}
+
+	fingerprint, bundleName := parts[0], parts[1]
+	bundleMap, err := bundleManager.ensureBuilt(bundleName)
+	if err != nil {
+		LogErrorf("Failed to build bundle %s: %s", bundleName, err)
+		http.Error(response, "Failed to build bundle", http.StatusInternalServerError)
+		return true
+	}
+
+	// Serve the pre-compressed variant accepted by the client, if there is one
+	etag := bundleMap.ETag()
+	var data []byte
+	if encoding := NegotiateEncoding(request.Header.Get("Accept-Encoding"), bundleManager.Encodings); encoding != nil {
+		if encoded, err := bundleManager.readArtifact(bundleMap, bundleName, encoding.Extension); err == nil {
+			data = encoded
+			etag = fmt.Sprintf("\"%s-%s\"", bundleMap.Hash, encoding.Name)
+			response.Header().Set("Content-Encoding", encoding.Name)
+		}
+	}
+
+	if data == nil {
+		if data, err = bundleManager.readArtifact(bundleMap, bundleName, ""); err != nil {
+			LogErrorf("Failed to read bundle %s: %s", bundleName, err)
+			http.Error(response, "Failed to read bundle", http.StatusInternalServerError)
+			return true
+		}
+	}
+
+	if len(bundleManager.Encodings) > 0 {
+		response.Header().Add("Vary", "Accept-Encoding")
+	}
+
+	if fingerprint == bundleMap.Fingerprint() {
+		response.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		response.Header().Set("Cache-Control", "no-cache")
+	}
+
+	if bundleMap.SourceMap {
+		response.Header().Set("SourceMap", path.Base(request.URL.Path)+".map")
+	}
+
+	response.Header().Set("Content-Type", bundleMap.MimeType)
+	response.Header().Set("ETag", etag)
+	http.ServeContent(response, request, bundleName, bundleMap.BuildDate, bytes.NewReader(data))
+	return true
+}
+
+// serveSourceMap serves the source map written next to the named bundle
+func (bundleManager *BundleManager) serveSourceMap(response http.ResponseWriter, request *http.Request, bundleName string) bool {
+	bundleMap, err := bundleManager.ensureBuilt(bundleName)
+	if err != nil || !bundleMap.SourceMap {
+		return false
+	}
+
+	data, err := bundleManager.readArtifact(bundleMap, bundleName, ".map")
+	if err != nil {
+		LogErrorf("Failed to read source map of bundle %s: %s", bundleName, err)
+		http.Error(response, "Failed to read source map", http.StatusInternalServerError)
+		return true
+	}
+
+	response.Header().Set("Cache-Control", "no-cache")
+	response.Header().Set("Content-Type", "application/json")
+	http.ServeContent(response, request, bundleName+".map", bundleMap.BuildDate, bytes.NewReader(data))
+	return true
+}
+
+// BuildBundle is used to compile the registered bundle defined by bundleName.
+// This method will delete the existing bundle file if it exists and replacing
+// with a newly built copy
+func (bundleManager *BundleManager) BuildBundle(bundleName string) error {
+	bundleManager.buildMutex.Lock()
+	bundleMap := bundleManager.Bundles[bundleName]
+	outputs := bundleManager.bundleOutputs()
+	bundleManager.buildMutex.Unlock()
+
+	return bundleManager.doBuild(bundleMap, bundleName, outputs)
+}
+
+// BuildAllBundles is used to build all of the currently registered content bundles, up to
+// MaxParallelBuilds bundles are built at once
+func (bundleManager *BundleManager) BuildAllBundles() error {
+	return bundleManager.buildParallel(func(bundleMap *BundleMap, outputs map[string]bool) (bool, error) {
+		return true, nil
+	})
+}
+
+// buildParallel builds the registered bundles that the provided filter selects using up to
+// MaxParallelBuilds goroutines, returning the first error encountered
+func (bundleManager *BundleManager) buildParallel(filter func(*BundleMap, map[string]bool) (bool, error)) error {
+	bundleManager.buildMutex.Lock()
+	bundles := make(map[string]*BundleMap, len(bundleManager.Bundles))
+	for name, bundleMap := range bundleManager.Bundles {
+		bundles[name] = bundleMap
+	}
+	outputs := bundleManager.bundleOutputs()
+	bundleManager.buildMutex.Unlock()
+
+	workers := bundleManager.MaxParallelBuilds
+	if workers <= 0 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	var errMutex sync.Mutex
+	var rtn error
+
+	limit := make(chan struct{}, workers)
+	for name, bundleMap := range bundles {
+		wg.Add(1)
+		limit <- struct{}{}
+
+		go func(name string, bundleMap *BundleMap) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+
+			build, err := filter(bundleMap, outputs)
+			if err == nil && build {
+				err = bundleManager.doBuild(bundleMap, name, outputs)
+			}
+
+			if err != nil {
+				errMutex.Lock()
+				if rtn == nil {
+					rtn = err
+				}
+				errMutex.Unlock()
+			}
+		}(name, bundleMap)
+	}
+
+	wg.Wait()
+	return rtn
+}
+
+// needsBuild returns true if the provided bundle has never been built, if its files have
+// been modified since it was built or if files have been added or removed from the bundle
+func (bundleManager *BundleManager) needsBuild(bundleMap *BundleMap, outputs map[string]bool) (bool, error) {
+	bundleMap = bundleManager.snapshot(bundleMap)
+	if bundleMap.BuildDate.IsZero() {
+		return true, nil
+	}
+
+	files, err := bundleMap.resolveFiles(outputs)
+	if err != nil {
+		return false, err
+	}
+
+	if bundleMap.filesChanged(files) {
+		return true, nil
+	}
+
+	for _, filename := range files {
+		si, err := statFile(filename)
+		if err != nil {
+			return false, err
+		}
+
+		if si.ModTime().After(bundleMap.BuildDate) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// RebuildBundle is used to compare the last modified times of the files in a content
+// bundle to the creation time of this content bundle, if files have been modified
+// (or added / removed) since this bundle was built it will be built a new. Returns nil
+// if no need to build
+func (bundleManager *BundleManager) RebuildBundle(bundleName string) error {
+	bundleManager.buildMutex.Lock()
+	bundleMap := bundleManager.Bundles[bundleName]
+	outputs := bundleManager.bundleOutputs()
+	bundleManager.buildMutex.Unlock()
+
+	if bundleMap == nil {
+		return fmt.Errorf("No bundle found named: %s", bundleName)
+	}
+
+	return bundleManager.rebuild(bundleMap, bundleName, outputs)
+}
+
+// rebuild builds the provided bundle map if its files have changed since it was built
+func (bundleManager *BundleManager) rebuild(bundleMap *BundleMap, bundleName string, outputs map[string]bool) error {
+	build, err := bundleManager.needsBuild(bundleMap, outputs)
+	if err != nil {
+		return err
+	}
+
+	if build {
+		return bundleManager.doBuild(bundleMap, bundleName, outputs)
+	}
+
+	return nil
+}
+
+// RebuildAllBundles is used to loop through all of the registered content bundles
+// and compare file modification dates to the build time of the bundle. If files
+// have been modified since this bundle was built, it will be built a new
+func (bundleManager *BundleManager) RebuildAllBundles() error {
+	return bundleManager.buildParallel(bundleManager.needsBuild)
+}
+
+// SourceFilenames returns the full path and filename of every source file used by the registered
+// content bundles, used by the Watcher to monitor the bundles for changes
+func (bundleManager *BundleManager) SourceFilenames() []string {
+	bundleManager.buildMutex.Lock()
+	bundles := make([]*BundleMap, 0, len(bundleManager.Bundles))
+	for _, bundleMap := range bundleManager.Bundles {
+		bundles = append(bundles, bundleMap)
+	}
+	outputs := bundleManager.bundleOutputs()
+	bundleManager.buildMutex.Unlock()
+
+	rtn := []string{}
+	seen := make(map[string]bool, 0)
+	for _, bundleMap := range bundles {
+		files, err := bundleMap.resolveFiles(outputs)
+		if err != nil {
+			LogWarningf("Failed to resolve bundle files: %s", err)
+			continue
+		}
+
+		for _, filename := range files {
+			if !seen[filename] {
+				seen[filename] = true
+				rtn = append(rtn, filename)
+			}
+		}
+	}
+
+	return rtn
+}
+
+// RebuildAffectedBundles rebuilds the registered content bundles that use any of the provided
+// full path and filenames (including files removed since the last build)
+func (bundleManager *BundleManager) RebuildAffectedBundles(changedFiles []string) error {
+	changed := make(map[string]bool, len(changedFiles))
+	for _, filename := range changedFiles {
+		changed[filename] = true
+	}
+
+	return bundleManager.buildParallel(func(bundleMap *BundleMap, outputs map[string]bool) (bool, error) {
+		files, err := bundleMap.resolveFiles(outputs)
+		if err != nil {
+			return false, err
+		}
+
+		previous := bundleManager.snapshot(bundleMap).SourceFiles
+
+		for _, filename := range append(files, previous...) {
+			if changed[filename] {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	})
+}
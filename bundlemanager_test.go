@@ -1,189 +1,680 @@
-/*
-	Digivance MVC Application Framework - Unit Tests
-	Bundle Manager Tests
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines the version 0.3.0 compatibility of bundlemanager.go functions. These functions are written
-	to demonstrate and test the intended use cases of the functions in bundlemanager.go
-*/
-
-package mvcapp_test
-
-import (
-	"fmt"
-	"io/ioutil"
-	"os"
-	"testing"
-	"time"
-
-	"github.com/digivance/mvcapp"
-)
-
-// TestBundleManager_CreateBundle ensures that the BundleManager.CreateBundle method operates as expected
-func TestBundleManager_CreateBundle(t *testing.T) {
-	bundleManager := mvcapp.NewBundleManager()
-	if err := bundleManager.CreateBundle("TestBundle", "text/css", []string{}); err != nil {
-		t.Errorf("Failed to create new bundle: %s", err)
-	}
-
-	if err := bundleManager.CreateBundle("TestBundle", "text/css", []string{}); err == nil {
-		t.Errorf("Failed to prevent duplicate bundle creation: %s", err)
-	}
-}
-
-// TestBundleManager_BuildBundle ensures that the BundleManager.BuildBundle and BundleManager.RebuildBundle method operates
-// as expected
-func TestBundleManager_BuildBundle(t *testing.T) {
-	expectedResult := "html,body{font-size:14px}a{color:blue}div{border:0}"
-	filename := []string{
-		fmt.Sprintf("%s/a.css", mvcapp.GetApplicationPath()),
-		fmt.Sprintf("%s/b.css", mvcapp.GetApplicationPath()),
-		fmt.Sprintf("%s/c.css", mvcapp.GetApplicationPath()),
-	}
-
-	if err := ioutil.WriteFile(filename[0], []byte("html, body { font-size: 14px; }\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[0])
-
-	if err := ioutil.WriteFile(filename[1], []byte("\ta {color: blue; }\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[1])
-
-	if err := ioutil.WriteFile(filename[2], []byte("\n\ndiv   {    border:    0;\t\t}\n\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[2])
-
-	bundleManager := mvcapp.NewBundleManager()
-	if err := bundleManager.BuildBundle("fail.js"); err == nil {
-		t.Error("Failed to prevent build of unknown bundle")
-	}
-
-	if err := bundleManager.CreateBundle("fail.js", "text/javascript", []string{}); err != nil {
-		t.Errorf("Failed to create empty bundle for testing (this is a good thing?): %s", err)
-	}
-
-	if err := bundleManager.BuildBundle("fail.js"); err == nil {
-		t.Error("Failed to prevent build of empty bundle")
-	}
-
-	bundleManager.Bundles["fail.js"].Files = append([]string{}, "notfound.js")
-	if err := bundleManager.BuildBundle("fail.js"); err == nil {
-		t.Error("Failed to prevent build with missing filename")
-	}
-
-	if err := bundleManager.RemoveBundle("fail.js"); err != nil {
-		t.Errorf("Failed to remove failing bundle: %s", err)
-	}
-
-	if err := bundleManager.RemoveBundle("fail.js"); err == nil {
-		t.Error("Failed to prevent removing non existent bundle")
-	}
-
-	defer os.RemoveAll("bundle")
-	if err := bundleManager.CreateBundle("styles.css", "text/css", filename); err != nil {
-		t.Fatalf("Failed to create bundle: %s", err)
-	}
-
-	if err := bundleManager.BuildBundle("styles.css"); err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll("bundle/styles.css")
-
-	if err := bundleManager.BuildBundle("«╗╣╟/failme"); err == nil {
-		t.Error("Failed to fail when using an invalid bundle file name")
-	}
-
-	bundleFilename := fmt.Sprintf("%s/bundle/styles.css", mvcapp.GetApplicationPath())
-	data, err := ioutil.ReadFile(bundleFilename)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	content := string(data)
-	if content != expectedResult {
-		t.Fatalf("Failed to build test bundle:\n> Expected: %s\n Received: %s", expectedResult, content)
-	}
-
-	bundleName := "styles.css"
-	if err := bundleManager.RebuildBundle(bundleName); err != nil {
-		t.Fatalf("Failed to not rebuild test bundle: %s", err)
-	}
-
-	bundleManager.Bundles[bundleName].BuildDate = time.Time{}
-
-	if err := bundleManager.RebuildBundle(bundleName); err != nil {
-		t.Fatalf("Failed to not rebuild test bundle: %s", err)
-	}
-
-	bundleManager.Bundles[bundleName].BuildDate = time.Time{}.Add(48 * time.Hour)
-
-	if err := bundleManager.RebuildBundle(bundleName); err != nil {
-		t.Fatalf("Failed to not rebuild test bundle: %s", err)
-	}
-}
-
-// TestBundleManager_BuildAllBundles ensures that the BundleManager.BuildAllBundles and BundleManager.RebuildAllBundles
-// methods operate as expected
-func TestBundleManagerBuildAllBundles(t *testing.T) {
-	expectedResult := "html,body{font-size:14px}a{color:blue}div{border:0}"
-	filename := []string{
-		fmt.Sprintf("%s/a.css", mvcapp.GetApplicationPath()),
-		fmt.Sprintf("%s/b.css", mvcapp.GetApplicationPath()),
-		fmt.Sprintf("%s/c.css", mvcapp.GetApplicationPath()),
-	}
-
-	if err := ioutil.WriteFile(filename[0], []byte("html, body { font-size: 14px; }\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[0])
-
-	if err := ioutil.WriteFile(filename[1], []byte("\ta {color: blue; }\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[1])
-
-	if err := ioutil.WriteFile(filename[2], []byte("\n\ndiv   {    border:    0;\t\t}\n\n"), 0644); err != nil {
-		t.Fatalf("Failed to write a.css: %s", err)
-	}
-	defer os.RemoveAll(filename[2])
-
-	bundleManager := mvcapp.NewBundleManager()
-	defer os.RemoveAll("bundle")
-	if err := bundleManager.CreateBundle("styles.css", "text/css", filename); err != nil {
-		t.Fatalf("Failed to create bundle: %s", err)
-	}
-
-	if err := bundleManager.BuildAllBundles(); err != nil {
-		t.Fatal(err)
-	}
-	defer os.RemoveAll("bundle/styles.css")
-
-	bundleFilename := fmt.Sprintf("%s/bundle/styles.css", mvcapp.GetApplicationPath())
-	data, err := ioutil.ReadFile(bundleFilename)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	content := string(data)
-	if content != expectedResult {
-		t.Fatalf("Failed to build test bundle:\n> Expected: %s\n Received: %s", expectedResult, content)
-	}
-
-	if err := bundleManager.RebuildAllBundles(); err != nil {
-		t.Fatalf("Failed to not rebuild all bundles: %s", err)
-	}
-
-	bundleName := "styles.css"
-	bundleManager.Bundles[bundleName].BuildDate = time.Time{}
-	if err := bundleManager.RebuildAllBundles(); err != nil {
-		t.Fatalf("Failed to rebuild all bundles: %s", err)
-	}
-
-	bundleManager.Bundles[bundleName].BuildDate = time.Time{}.Add(48 * time.Hour)
-	if err := bundleManager.RebuildAllBundles(); err != nil {
-		t.Fatalf("Failed to rebuild all bundles: %s", err)
-	}
-}
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Bundle Manager Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of bundlemanager.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in bundlemanager.go
+*/
+
+package mvcapp_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestBundleManager_CreateBundle ensures that the BundleManager.CreateBundle method operates as expected
+func TestBundleManager_CreateBundle(t *testing.T) {
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("TestBundle", "text/css", []string{}); err != nil {
+		t.Errorf("Failed to create new bundle: %s", err)
+	}
+
+	if err := bundleManager.CreateBundle("TestBundle", "text/css", []string{}); err == nil {
+		t.Errorf("Failed to prevent duplicate bundle creation: %s", err)
+	}
+}
+
+// TestBundleManager_BuildBundle ensures that the BundleManager.BuildBundle and BundleManager.RebuildBundle method operates
+// as expected
+func TestBundleManager_BuildBundle(t *testing.T) {
+	expectedResult := "html,body{font-size:14px}a{color:blue}div{border:0}\n/*# sourceMappingURL=styles.css.map */"
+	filename := []string{
+		fmt.Sprintf("%s/a.css", mvcapp.GetApplicationPath()),
+		fmt.Sprintf("%s/b.css", mvcapp.GetApplicationPath()),
+		fmt.Sprintf("%s/c.css", mvcapp.GetApplicationPath()),
+	}
+
+	if err := ioutil.WriteFile(filename[0], []byte("html, body { font-size: 14px; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[0])
+
+	if err := ioutil.WriteFile(filename[1], []byte("\ta {color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[1])
+
+	if err := ioutil.WriteFile(filename[2], []byte("\n\ndiv   {    border:    0;\t\t}\n\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[2])
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.BuildBundle("fail.js"); err == nil {
+		t.Error("Failed to prevent build of unknown bundle")
+	}
+
+	if err := bundleManager.CreateBundle("fail.js", "text/javascript", []string{}); err != nil {
+		t.Errorf("Failed to create empty bundle for testing (this is a good thing?): %s", err)
+	}
+
+	if err := bundleManager.BuildBundle("fail.js"); err == nil {
+		t.Error("Failed to prevent build of empty bundle")
+	}
+
+	bundleManager.Bundles["fail.js"].Files = append([]string{}, "notfound.js")
+	if err := bundleManager.BuildBundle("fail.js"); err == nil {
+		t.Error("Failed to prevent build with missing filename")
+	}
+
+	if err := bundleManager.RemoveBundle("fail.js"); err != nil {
+		t.Errorf("Failed to remove failing bundle: %s", err)
+	}
+
+	if err := bundleManager.RemoveBundle("fail.js"); err == nil {
+		t.Error("Failed to prevent removing non existent bundle")
+	}
+
+	defer os.RemoveAll("bundle")
+	if err := bundleManager.CreateBundle("styles.css", "text/css", filename); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	if err := bundleManager.BuildBundle("styles.css"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("bundle/styles.css")
+
+	if err := bundleManager.BuildBundle("«╗╣╟/failme"); err == nil {
+		t.Error("Failed to fail when using an invalid bundle file name")
+	}
+
+	bundleFilename := fmt.Sprintf("%s/bundle/styles.css", mvcapp.GetApplicationPath())
+	data, err := ioutil.ReadFile(bundleFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := string(data)
+	if content != expectedResult {
+		t.Fatalf("Failed to build test bundle:\n> Expected: %s\n Received: %s", expectedResult, content)
+	}
+
+	bundleName := "styles.css"
+	if err := bundleManager.RebuildBundle(bundleName); err != nil {
+		t.Fatalf("Failed to not rebuild test bundle: %s", err)
+	}
+
+	bundleManager.Bundles[bundleName].BuildDate = time.Time{}
+
+	if err := bundleManager.RebuildBundle(bundleName); err != nil {
+		t.Fatalf("Failed to not rebuild test bundle: %s", err)
+	}
+
+	bundleManager.Bundles[bundleName].BuildDate = time.Time{}.Add(48 * time.Hour)
+
+	if err := bundleManager.RebuildBundle(bundleName); err != nil {
+		t.Fatalf("Failed to not rebuild test bundle: %s", err)
+	}
+}
+
+// TestBundleManager_BuildAllBundles ensures that the BundleManager.BuildAllBundles and BundleManager.RebuildAllBundles
+// methods operate as expected
+func TestBundleManagerBuildAllBundles(t *testing.T) {
+	expectedResult := "html,body{font-size:14px}a{color:blue}div{border:0}\n/*# sourceMappingURL=styles.css.map */"
+	filename := []string{
+		fmt.Sprintf("%s/a.css", mvcapp.GetApplicationPath()),
+		fmt.Sprintf("%s/b.css", mvcapp.GetApplicationPath()),
+		fmt.Sprintf("%s/c.css", mvcapp.GetApplicationPath()),
+	}
+
+	if err := ioutil.WriteFile(filename[0], []byte("html, body { font-size: 14px; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[0])
+
+	if err := ioutil.WriteFile(filename[1], []byte("\ta {color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[1])
+
+	if err := ioutil.WriteFile(filename[2], []byte("\n\ndiv   {    border:    0;\t\t}\n\n"), 0644); err != nil {
+		t.Fatalf("Failed to write a.css: %s", err)
+	}
+	defer os.RemoveAll(filename[2])
+
+	bundleManager := mvcapp.NewBundleManager()
+	defer os.RemoveAll("bundle")
+	if err := bundleManager.CreateBundle("styles.css", "text/css", filename); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	if err := bundleManager.BuildAllBundles(); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll("bundle/styles.css")
+
+	bundleFilename := fmt.Sprintf("%s/bundle/styles.css", mvcapp.GetApplicationPath())
+	data, err := ioutil.ReadFile(bundleFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := string(data)
+	if content != expectedResult {
+		t.Fatalf("Failed to build test bundle:\n> Expected: %s\n Received: %s", expectedResult, content)
+	}
+
+	if err := bundleManager.RebuildAllBundles(); err != nil {
+		t.Fatalf("Failed to not rebuild all bundles: %s", err)
+	}
+
+	bundleName := "styles.css"
+	bundleManager.Bundles[bundleName].BuildDate = time.Time{}
+	if err := bundleManager.RebuildAllBundles(); err != nil {
+		t.Fatalf("Failed to rebuild all bundles: %s", err)
+	}
+
+	bundleManager.Bundles[bundleName].BuildDate = time.Time{}.Add(48 * time.Hour)
+	if err := bundleManager.RebuildAllBundles(); err != nil {
+		t.Fatalf("Failed to rebuild all bundles: %s", err)
+	}
+}
+
+// TestBundleManager_ServeBundle ensures that the BundleManager.BundleURL, BundleManager.BundleTag and
+// BundleManager.ServeBundle methods operate as expected
+func TestBundleManager_ServeBundle(t *testing.T) {
+	filename := fmt.Sprintf("%s/serve.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write serve.css: %s", err)
+	}
+	defer os.RemoveAll(filename)
+	defer os.RemoveAll("bundle")
+
+	bundleManager := mvcapp.NewBundleManager()
+	if _, err := bundleManager.BundleURL("site.css"); err == nil {
+		t.Error("Failed to prevent url of unknown bundle")
+	}
+
+	if err := bundleManager.CreateBundle("site.css", "text/css", []string{filename}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	url, err := bundleManager.BundleURL("site.css")
+	if err != nil {
+		t.Fatalf("Failed to get bundle url: %s", err)
+	}
+
+	fingerprint := bundleManager.Bundles["site.css"].Fingerprint()
+	if len(fingerprint) != 16 || url != fmt.Sprintf("/bundle/%s/site.css", fingerprint) {
+		t.Errorf("Failed to fingerprint bundle url: %s", url)
+	}
+
+	tag, err := bundleManager.BundleTag("site.css")
+	if err != nil || !strings.HasPrefix(string(tag), "<link rel=\"stylesheet\"") || !strings.Contains(string(tag), url) {
+		t.Errorf("Failed to create bundle tag: %s (%v)", tag, err)
+	}
+
+	request := httptest.NewRequest("GET", "/home/index", nil)
+	if bundleManager.ServeBundle(httptest.NewRecorder(), request) {
+		t.Error("Failed to ignore non bundle request")
+	}
+
+	response := httptest.NewRecorder()
+	if !bundleManager.ServeBundle(response, httptest.NewRequest("GET", url, nil)) {
+		t.Fatal("Failed to serve bundle request")
+	}
+
+	if response.Code != 200 || response.Body.String() != "a{color:blue}\n/*# sourceMappingURL=site.css.map */" {
+		t.Errorf("Failed to serve bundle content, received %d: %s", response.Code, response.Body.String())
+	}
+
+	if response.Header().Get("Content-Type") != "text/css" ||
+		response.Header().Get("ETag") != bundleManager.Bundles["site.css"].ETag() ||
+		!strings.Contains(response.Header().Get("Cache-Control"), "immutable") {
+		t.Errorf("Failed to set bundle headers: %v", response.Header())
+	}
+
+	request = httptest.NewRequest("GET", url, nil)
+	request.Header.Set("If-None-Match", bundleManager.Bundles["site.css"].ETag())
+	response = httptest.NewRecorder()
+	bundleManager.ServeBundle(response, request)
+	if response.Code != http.StatusNotModified {
+		t.Errorf("Failed to honor If-None-Match, received %d", response.Code)
+	}
+
+	response = httptest.NewRecorder()
+	bundleManager.ServeBundle(response, httptest.NewRequest("GET", "/bundle/stale/site.css", nil))
+	if response.Code != 200 || response.Header().Get("Cache-Control") != "no-cache" {
+		t.Errorf("Failed to serve stale fingerprint without caching: %v", response.Header())
+	}
+}
+
+// TestBundleManager_DevelopmentMode ensures that the BundleManager serves individual source files and
+// rebuilds changed bundles on request when in development mode
+func TestBundleManager_DevelopmentMode(t *testing.T) {
+	filename := []string{
+		fmt.Sprintf("%s/dev_a.js", mvcapp.GetApplicationPath()),
+		fmt.Sprintf("%s/dev_b.js", mvcapp.GetApplicationPath()),
+	}
+
+	for _, name := range filename {
+		if err := ioutil.WriteFile(name, []byte("var  value = 1;\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %s", name, err)
+		}
+		defer os.RemoveAll(name)
+	}
+	defer os.RemoveAll("bundle")
+
+	config := mvcapp.NewConfigurationManager()
+	config.BundleDevelopmentMode = true
+
+	bundleManager := mvcapp.NewBundleManagerFromConfig(config)
+	if err := bundleManager.CreateBundle("site.js", "text/javascript", filename); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	tag, err := bundleManager.BundleTag("site.js")
+	if err != nil || strings.Count(string(tag), "<script") != 2 || !strings.Contains(string(tag), "/bundle/dev/site.js/1?v=") {
+		t.Fatalf("Failed to render one tag per source file: %s (%v)", tag, err)
+	}
+
+	response := httptest.NewRecorder()
+	if !bundleManager.ServeBundle(response, httptest.NewRequest("GET", "/bundle/dev/site.js/0", nil)) {
+		t.Fatal("Failed to serve bundle source file")
+	}
+
+	if response.Code != 200 || response.Body.String() != "var  value = 1;\n" {
+		t.Errorf("Failed to serve unminified source, received %d: %s", response.Code, response.Body.String())
+	}
+
+	if bundleManager.ServeBundle(httptest.NewRecorder(), httptest.NewRequest("GET", "/bundle/dev/site.js/9", nil)) {
+		t.Error("Failed to ignore unknown source file index")
+	}
+
+	before, err := bundleManager.BundleURL("site.js")
+	if err != nil {
+		t.Fatalf("Failed to build bundle: %s", err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := ioutil.WriteFile(filename[1], []byte("var other = 2;\n"), 0644); err != nil {
+		t.Fatalf("Failed to update %s: %s", filename[1], err)
+	}
+	os.Chtimes(filename[1], later, later)
+
+	after, err := bundleManager.BundleURL("site.js")
+	if err != nil || after == before {
+		t.Errorf("Failed to rebuild changed bundle on request: %s (%v)", after, err)
+	}
+
+	bundleManager.DevelopmentMode = false
+	if tag, err := bundleManager.BundleTag("site.js"); err != nil || strings.Count(string(tag), "<script") != 1 {
+		t.Errorf("Failed to render single bundle tag in production: %s (%v)", tag, err)
+	}
+}
+
+// TestBundleMap_ResolveFiles ensures that the BundleMap.ResolveFiles method expands directories, glob
+// patterns and exclusions and applies the ordering rules as expected
+func TestBundleMap_ResolveFiles(t *testing.T) {
+	root := fmt.Sprintf("%s/_test_static", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(root)
+
+	files := []string{
+		"js/app.js",
+		"js/pages/home.js",
+		"js/pages/home.min.js",
+		"js/vendor/jquery.js",
+		"css/site.css",
+	}
+
+	for _, name := range files {
+		filename := fmt.Sprintf("%s/%s", root, name)
+		os.MkdirAll(filename[:strings.LastIndex(filename, "/")], 0755)
+		if err := ioutil.WriteFile(filename, []byte("var a = 1;\n"), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %s", name, err)
+		}
+	}
+
+	if !mvcapp.MatchBundlePattern("a/**/*.js", "a/b/c/d.js") || !mvcapp.MatchBundlePattern("a/**/*.js", "a/d.js") ||
+		mvcapp.MatchBundlePattern("a/*.js", "a/b/d.js") {
+		t.Error("Failed to match bundle patterns")
+	}
+
+	bundleMap := mvcapp.NewBundleMap("text/javascript", []string{"_test_static/js/**/*.js", "!_test_static/**/*.min.js"})
+	bundleMap.Order = []string{"**/vendor/**"}
+
+	resolved, err := bundleMap.ResolveFiles()
+	if err != nil {
+		t.Fatalf("Failed to resolve files: %s", err)
+	}
+
+	expected := []string{
+		fmt.Sprintf("%s/js/vendor/jquery.js", root),
+		fmt.Sprintf("%s/js/app.js", root),
+		fmt.Sprintf("%s/js/pages/home.js", root),
+	}
+
+	if strings.Join(resolved, ",") != strings.Join(expected, ",") {
+		t.Errorf("Failed to resolve files:\n> Expected: %v\n Received: %v", expected, resolved)
+	}
+
+	bundleMap.Files = []string{"_test_static/css"}
+	if resolved, err := bundleMap.ResolveFiles(); err != nil || len(resolved) != 1 {
+		t.Errorf("Failed to resolve directory: %v (%v)", resolved, err)
+	}
+
+	defer os.RemoveAll("bundle")
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("site.js", "text/javascript", []string{"_test_static/js/**/*.js"}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	if err := bundleManager.BuildBundle("site.js"); err != nil {
+		t.Fatalf("Failed to build bundle: %s", err)
+	}
+
+	if len(bundleManager.Bundles["site.js"].SourceFiles) != 4 {
+		t.Errorf("Failed to record bundle source files: %v", bundleManager.Bundles["site.js"].SourceFiles)
+	}
+
+	if err := ioutil.WriteFile(fmt.Sprintf("%s/js/added.js", root), []byte("var b = 2;\n"), 0644); err != nil {
+		t.Fatalf("Failed to write added.js: %s", err)
+	}
+
+	if err := bundleManager.RebuildBundle("site.js"); err != nil {
+		t.Fatalf("Failed to rebuild bundle: %s", err)
+	}
+
+	if len(bundleManager.Bundles["site.js"].SourceFiles) != 5 {
+		t.Errorf("Failed to pick up new file on rebuild: %v", bundleManager.Bundles["site.js"].SourceFiles)
+	}
+}
+
+// TestBundleManager_RootPattern ensures that glob patterns walking from the application path skip
+// hidden folders and the built bundles, so that a rebuild doesn't bundle the previous output
+func TestBundleManager_RootPattern(t *testing.T) {
+	source := fmt.Sprintf("%s/_glob_a.js", mvcapp.GetApplicationPath())
+	hidden := fmt.Sprintf("%s/.glob_hidden", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(source)
+	defer os.RemoveAll(hidden)
+	defer os.RemoveAll("bundle")
+
+	os.MkdirAll(hidden, 0755)
+	if err := ioutil.WriteFile(source, []byte("var a = 1;\n"), 0644); err != nil {
+		t.Fatalf("Failed to write _glob_a.js: %s", err)
+	}
+
+	if err := ioutil.WriteFile(fmt.Sprintf("%s/_glob_b.js", hidden), []byte("var b = 2;\n"), 0644); err != nil {
+		t.Fatalf("Failed to write _glob_b.js: %s", err)
+	}
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("_glob_site.js", "text/javascript", []string{"**/_glob_*.js"}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := bundleManager.BuildBundle("_glob_site.js"); err != nil {
+			t.Fatalf("Failed to build bundle: %s", err)
+		}
+
+		sources := bundleManager.Bundles["_glob_site.js"].SourceFiles
+		if len(sources) != 1 || sources[0] != source {
+			t.Fatalf("Failed to skip hidden folders and bundle outputs: %v", sources)
+		}
+	}
+
+	if filenames := bundleManager.SourceFilenames(); len(filenames) != 1 || filenames[0] != source {
+		t.Errorf("Failed to skip bundle outputs in the watched files: %v", filenames)
+	}
+}
+
+// TestBundleManager_SourceMapsAndURLs ensures that css url references are rewritten relative to the
+// bundle url and that source maps are written and served next to the bundle
+func TestBundleManager_SourceMapsAndURLs(t *testing.T) {
+	root := fmt.Sprintf("%s/_test_assets", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(root)
+	defer os.RemoveAll("bundle")
+
+	os.MkdirAll(fmt.Sprintf("%s/css/theme", root), 0755)
+	files := map[string]string{
+		"css/site.css":       "body { background: url(../img/bg.png); }\n",
+		"css/theme/dark.css": "a { background: url('../../fonts/x.woff?v=1'); b: url(data:image/png;base64,AA); c: url(/abs.png); }\n",
+	}
+
+	for name, content := range files {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %s", name, err)
+		}
+	}
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("site.css", "text/css", []string{"_test_assets/css/site.css", "_test_assets/css/theme/dark.css"}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	if err := bundleManager.BuildBundle("site.css"); err != nil {
+		t.Fatalf("Failed to build bundle: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(bundleManager.BundleFilename("site.css"))
+	if err != nil {
+		t.Fatalf("Failed to read bundle: %s", err)
+	}
+
+	content := string(data)
+	if !strings.Contains(content, "url(../../_test_assets/img/bg.png)") ||
+		!strings.Contains(content, "../../_test_assets/fonts/x.woff?v=1") ||
+		!strings.Contains(content, "url(data:image/png;base64,AA)") || !strings.Contains(content, "url(/abs.png)") {
+		t.Errorf("Failed to rewrite css urls: %s", content)
+	}
+
+	url, _ := bundleManager.BundleURL("site.css")
+	response := httptest.NewRecorder()
+	bundleManager.ServeBundle(response, httptest.NewRequest("GET", url, nil))
+	if response.Header().Get("SourceMap") != "site.css.map" {
+		t.Errorf("Failed to reference source map: %v", response.Header())
+	}
+
+	response = httptest.NewRecorder()
+	if !bundleManager.ServeBundle(response, httptest.NewRequest("GET", url+".map", nil)) || response.Code != 200 {
+		t.Fatalf("Failed to serve source map, received %d", response.Code)
+	}
+
+	sourceMap := map[string]interface{}{}
+	if err := json.Unmarshal(response.Body.Bytes(), &sourceMap); err != nil {
+		t.Fatalf("Failed to read source map: %s", err)
+	}
+
+	sources, _ := sourceMap["sources"].([]interface{})
+	if len(sources) != 2 || sources[0] != "_test_assets/css/site.css" || sourceMap["mappings"] == "" {
+		t.Errorf("Failed to create source map: %s", response.Body.String())
+	}
+}
+
+// TestBundleManager_Integrity ensures that bundle tags include the SRI hash and the CDN fallback loader
+func TestBundleManager_Integrity(t *testing.T) {
+	filename := fmt.Sprintf("%s/integrity.js", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("var a = 1;\n"), 0644); err != nil {
+		t.Fatalf("Failed to write integrity.js: %s", err)
+	}
+	defer os.RemoveAll(filename)
+	stylesheet := fmt.Sprintf("%s/integrity.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(stylesheet, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write integrity.css: %s", err)
+	}
+	defer os.RemoveAll(stylesheet)
+	defer os.RemoveAll("bundle")
+
+	bundleManager := mvcapp.NewBundleManager()
+	bundleManager.CreateBundle("site.js", "text/javascript", []string{filename})
+	bundleManager.CreateBundle("site.css", "text/css", []string{stylesheet})
+
+	integrity, err := bundleManager.BundleIntegrity("site.js")
+	if err != nil || !strings.HasPrefix(integrity, "sha384-") || len(integrity) != 71 {
+		t.Fatalf("Failed to compute integrity hash: %s (%v)", integrity, err)
+	}
+
+	tag, err := bundleManager.BundleTag("site.js")
+	if err != nil || !strings.Contains(string(tag), fmt.Sprintf("integrity=\"%s\"", integrity)) {
+		t.Errorf("Failed to add integrity to bundle tag: %s (%v)", tag, err)
+	}
+
+	bundleManager.Bundles["site.js"].CDNURL = "https://cdn.example.com/site.js"
+	tag, err = bundleManager.BundleTag("site.js")
+	if err != nil || !strings.Contains(string(tag), "src=\"https://cdn.example.com/site.js\"") ||
+		!strings.Contains(string(tag), "crossorigin=\"anonymous\"") || !strings.Contains(string(tag), "document.write(") {
+		t.Errorf("Failed to create CDN fallback tag: %s (%v)", tag, err)
+	}
+
+	bundleManager.Bundles["site.css"].CDNURL = "https://cdn.example.com/site.css"
+	tag, err = bundleManager.BundleTag("site.css")
+	if err != nil || !strings.Contains(string(tag), "href=\"https://cdn.example.com/site.css\"") || !strings.Contains(string(tag), "onerror=") {
+		t.Errorf("Failed to create CDN fallback stylesheet tag: %s (%v)", tag, err)
+	}
+}
+
+// TestBundleManager_InMemory ensures that bundles can be built in parallel and kept in memory
+func TestBundleManager_InMemory(t *testing.T) {
+	filename := fmt.Sprintf("%s/memory.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write memory.css: %s", err)
+	}
+	defer os.RemoveAll(filename)
+
+	config := mvcapp.NewConfigurationManager()
+	config.BundleInMemory = true
+	config.BundleMaxParallelBuilds = 2
+	config.BundleManifest = ""
+
+	bundleManager := mvcapp.NewBundleManagerFromConfig(config)
+	for i := 0; i < 5; i++ {
+		bundleManager.CreateBundle(fmt.Sprintf("memory%d.css", i), "text/css", []string{filename})
+	}
+
+	if err := bundleManager.BuildAllBundles(); err != nil {
+		t.Fatalf("Failed to build bundles in memory: %s", err)
+	}
+
+	if _, err := os.Stat(bundleManager.BundleFilename("memory0.css")); !os.IsNotExist(err) {
+		t.Error("Failed to keep bundle in memory, bundle file was written")
+	}
+
+	url, _ := bundleManager.BundleURL("memory3.css")
+	response := httptest.NewRecorder()
+	bundleManager.ServeBundle(response, httptest.NewRequest("GET", url, nil))
+	if response.Code != 200 || response.Body.String() != "a{color:blue}\n/*# sourceMappingURL=memory3.css.map */" {
+		t.Errorf("Failed to serve bundle from memory, received %d: %s", response.Code, response.Body.String())
+	}
+
+	bundleManager.CreateBundle("missing.css", "text/css", []string{"not_found.css"})
+	if err := bundleManager.RebuildAllBundles(); err == nil {
+		t.Error("Failed to report error from parallel build")
+	}
+}
+
+// TestBundleManager_RebuildWhileServing ensures that bundles can be served and tagged while they
+// are rebuilt (run with -race)
+func TestBundleManager_RebuildWhileServing(t *testing.T) {
+	filename := fmt.Sprintf("%s/serving.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write serving.css: %s", err)
+	}
+	defer os.RemoveAll(filename)
+
+	config := mvcapp.NewConfigurationManager()
+	config.BundleInMemory = true
+	config.BundleManifest = ""
+
+	bundleManager := mvcapp.NewBundleManagerFromConfig(config)
+	bundleManager.CreateBundle("serving.css", "text/css", []string{filename})
+	if err := bundleManager.BuildAllBundles(); err != nil {
+		t.Fatalf("Failed to build bundle: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := bundleManager.RebuildAffectedBundles([]string{filename}); err != nil {
+					t.Errorf("Failed to rebuild bundle: %s", err)
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				url, err := bundleManager.BundleURL("serving.css")
+				if err != nil {
+					t.Errorf("Failed to get bundle url: %s", err)
+					continue
+				}
+
+				response := httptest.NewRecorder()
+				bundleManager.ServeBundle(response, httptest.NewRequest("GET", url, nil))
+				if response.Code != 200 {
+					t.Errorf("Failed to serve bundle while rebuilding, received %d", response.Code)
+				}
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := bundleManager.BundleTag("serving.css"); err != nil {
+					t.Errorf("Failed to create bundle tag while rebuilding: %s", err)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+// TestBundleManager_AtomicBuild ensures that rebuilding a bundle replaces the file without leaving
+// temporary files behind
+func TestBundleManager_AtomicBuild(t *testing.T) {
+	filename := fmt.Sprintf("%s/atomic.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(filename, []byte("a { color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write atomic.css: %s", err)
+	}
+	defer os.RemoveAll(filename)
+	defer os.RemoveAll("bundle")
+
+	bundleManager := mvcapp.NewBundleManager()
+	bundleManager.CreateBundle("atomic.css", "text/css", []string{filename})
+	for i := 0; i < 2; i++ {
+		if err := bundleManager.BuildBundle("atomic.css"); err != nil {
+			t.Fatalf("Failed to build bundle: %s", err)
+		}
+	}
+
+	folder := fmt.Sprintf("%s/bundle", mvcapp.GetApplicationPath())
+	fi, err := os.Stat(folder)
+	if err != nil || fi.Mode().Perm()&0100 == 0 {
+		t.Errorf("Failed to create a usable bundle folder: %v", err)
+	}
+
+	entries, _ := ioutil.ReadDir(folder)
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp") {
+			t.Errorf("Failed to clean up temporary bundle file: %s", entry.Name())
+		}
+	}
+}
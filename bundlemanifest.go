@@ -0,0 +1,216 @@
+/*
+	Digivance MVC Application Framework
+	Bundle Manifest Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the bundle definition object and the loading of bundles.json manifests. This
+	allows front end developers to declare and change content bundles without recompiling the
+	application, the manifest is watched for changes while the application is running.
+*/
+
+package mvcapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BundleDefinition is a single content bundle as declared in a bundles.json manifest or in the
+// Bundles section of the configuration file
+type BundleDefinition struct {
+	// Name is the name of the bundle, used in urls and by the {{ Bundle "name" }} view function
+	Name string
+
+	// MimeType is the MIME type of the files in this bundle (E.g. text/css or text/javascript)
+	MimeType string
+
+	// Files is the list of filenames, directories, glob and exclusion patterns (see BundleMap.Files)
+	Files []string
+
+	// Order is the list of patterns moved to the front of the bundle (see BundleMap.Order)
+	Order []string
+
+	// Output is the optional path and filename the bundle is built to, relative to the application
+	// path (defaults to bundle/Name)
+	Output string
+
+	// CDNURL is an optional external url serving a copy of the bundle (see BundleMap.CDNURL)
+	CDNURL string
+
+	// Minify can be set to false to bundle the files without minification (defaults to true)
+	Minify *bool
+
+	// MinifyParams are optional parameters passed to the minifier of this bundle
+	MinifyParams map[string]string
+
+	// SourceMap can be set to false to skip writing the source map (defaults to true)
+	SourceMap *bool
+}
+
+// NewBundleMap returns a new BundleMap constructed from this definition
+func (definition *BundleDefinition) NewBundleMap() *BundleMap {
+	rtn := NewBundleMap(definition.MimeType, definition.Files)
+	rtn.Output = definition.Output
+	rtn.CDNURL = definition.CDNURL
+
+	if definition.Order != nil {
+		rtn.Order = definition.Order
+	}
+
+	if definition.Minify != nil {
+		rtn.Minify = *definition.Minify
+	}
+
+	if definition.SourceMap != nil {
+		rtn.SourceMap = *definition.SourceMap
+	}
+
+	if definition.MinifyParams != nil {
+		rtn.MinifyParams = definition.MinifyParams
+	}
+
+	return rtn
+}
+
+// ApplyBundleDefinitions registers the provided bundle definitions, replacing the bundles that
+// were registered by previous definitions. Bundles created with CreateBundle are left as is
+func (bundleManager *BundleManager) ApplyBundleDefinitions(definitions []*BundleDefinition) error {
+	bundles := make(map[string]*BundleMap, 0)
+	defined := make(map[string]bool, 0)
+
+	for _, definition := range definitions {
+		if definition == nil || definition.Name == "" {
+			return fmt.Errorf("Failed to apply bundle definitions, every bundle requires a Name")
+		}
+
+		if defined[definition.Name] {
+			return fmt.Errorf("Failed to apply bundle definitions, %s is defined more than once", definition.Name)
+		}
+
+		defined[definition.Name] = true
+		bundles[definition.Name] = definition.NewBundleMap()
+	}
+
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	for name, bundleMap := range bundleManager.Bundles {
+		if bundleManager.definedBundles[name] {
+			continue
+		}
+
+		if defined[name] {
+			return fmt.Errorf("Failed to apply bundle definitions, a bundle named %s was already created", name)
+		}
+
+		bundles[name] = bundleMap
+	}
+
+	bundleManager.Bundles = bundles
+	bundleManager.definedBundles = defined
+	return nil
+}
+
+// LoadManifest reads the bundle definitions from the provided json manifest (E.g. ./bundles.json)
+// and registers them. The manifest filename is remembered so that ReloadManifest and
+// WatchManifest can pick up changes
+func (bundleManager *BundleManager) LoadManifest(filename string) error {
+	if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
+		filename = GetApplicationPath() + filename[1:]
+	}
+
+	si, err := statFile(filename)
+	if err != nil {
+		return err
+	}
+
+	data, err := readFile(filename)
+	if err != nil {
+		return err
+	}
+
+	definitions := []*BundleDefinition{}
+	if err := json.Unmarshal(data, &definitions); err != nil {
+		return fmt.Errorf("Failed to read bundle manifest %s: %s", filename, err)
+	}
+
+	if err := bundleManager.ApplyBundleDefinitions(definitions); err != nil {
+		return err
+	}
+
+	bundleManager.buildMutex.Lock()
+	bundleManager.ManifestFilename = filename
+	bundleManager.manifestModTime = si.ModTime()
+	bundleManager.buildMutex.Unlock()
+
+	LogTracef("Loaded %d bundles from %s", len(definitions), filename)
+	return nil
+}
+
+// ReloadManifest reloads the bundle manifest if it has been modified since it was last loaded.
+// Returns nil if there is no manifest or no need to reload
+func (bundleManager *BundleManager) ReloadManifest() error {
+	bundleManager.buildMutex.Lock()
+	filename := bundleManager.ManifestFilename
+	modTime := bundleManager.manifestModTime
+	bundleManager.buildMutex.Unlock()
+
+	if filename == "" {
+		return nil
+	}
+
+	si, err := statFile(filename)
+	if err != nil {
+		return err
+	}
+
+	if !si.ModTime().After(modTime) {
+		return nil
+	}
+
+	return bundleManager.LoadManifest(filename)
+}
+
+// manifestFilename returns the filename of the loaded manifest, safe to call while the manifest
+// is reloaded
+func (bundleManager *BundleManager) manifestFilename() string {
+	bundleManager.buildMutex.Lock()
+	defer bundleManager.buildMutex.Unlock()
+
+	return bundleManager.ManifestFilename
+}
+
+// WatchManifest starts a goroutine that calls ReloadManifest every interval until
+// StopWatchingManifest is called
+func (bundleManager *BundleManager) WatchManifest(interval time.Duration) {
+	bundleManager.StopWatchingManifest()
+
+	stop := make(chan struct{})
+	bundleManager.manifestStop = stop
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := bundleManager.ReloadManifest(); err != nil {
+					LogErrorf("Failed to reload bundle manifest: %s", err)
+				}
+			}
+		}
+	}()
+}
+
+// StopWatchingManifest stops the goroutine started by WatchManifest
+func (bundleManager *BundleManager) StopWatchingManifest() {
+	if bundleManager.manifestStop != nil {
+		close(bundleManager.manifestStop)
+		bundleManager.manifestStop = nil
+	}
+}
@@ -0,0 +1,180 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Bundle Manifest Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of bundlemanifest.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in bundlemanifest.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestBundleManager_LoadManifest ensures that the BundleManager.LoadManifest and BundleManager.ReloadManifest
+// methods operate as expected
+func TestBundleManager_LoadManifest(t *testing.T) {
+	source := fmt.Sprintf("%s/manifest.css", mvcapp.GetApplicationPath())
+	if err := ioutil.WriteFile(source, []byte("a {  color: blue; }\n"), 0644); err != nil {
+		t.Fatalf("Failed to write manifest.css: %s", err)
+	}
+	defer os.RemoveAll(source)
+	defer os.RemoveAll("bundle")
+
+	manifest := fmt.Sprintf("%s/_test_bundles.json", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(manifest)
+
+	data := `[
+		{ "Name": "site.css", "MimeType": "text/css", "Files": [ "manifest.css" ] },
+		{ "Name": "raw.css", "MimeType": "text/css", "Files": [ "manifest.css" ], "Minify": false, "Output": "bundle/raw/site.css" }
+	]`
+
+	if err := ioutil.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.CreateBundle("code.css", "text/css", []string{source}); err != nil {
+		t.Fatalf("Failed to create bundle: %s", err)
+	}
+
+	if err := bundleManager.LoadManifest("./_test_bundles.json"); err != nil {
+		t.Fatalf("Failed to load manifest: %s", err)
+	}
+
+	if len(bundleManager.Bundles) != 3 {
+		t.Fatalf("Failed to register manifest bundles, found %d", len(bundleManager.Bundles))
+	}
+
+	if err := bundleManager.BuildAllBundles(); err != nil {
+		t.Fatalf("Failed to build manifest bundles: %s", err)
+	}
+
+	built, err := ioutil.ReadFile(fmt.Sprintf("%s/bundle/site.css", mvcapp.GetApplicationPath()))
+	if err != nil || string(built) != "a{color:blue}\n/*# sourceMappingURL=site.css.map */" {
+		t.Errorf("Failed to build minified manifest bundle: %s (%v)", built, err)
+	}
+
+	built, err = ioutil.ReadFile(fmt.Sprintf("%s/bundle/raw/site.css", mvcapp.GetApplicationPath()))
+	if err != nil || string(built) != "a {  color: blue; }\n/*# sourceMappingURL=raw.css.map */" {
+		t.Errorf("Failed to build unminified manifest bundle to output: %s (%v)", built, err)
+	}
+
+	if err := bundleManager.ReloadManifest(); err != nil {
+		t.Errorf("Failed to skip reloading unchanged manifest: %s", err)
+	}
+
+	data = `[ { "Name": "other.css", "MimeType": "text/css", "Files": [ "manifest.css" ] } ]`
+	if err := ioutil.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to update manifest: %s", err)
+	}
+
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(manifest, later, later)
+
+	if err := bundleManager.ReloadManifest(); err != nil {
+		t.Fatalf("Failed to reload manifest: %s", err)
+	}
+
+	if len(bundleManager.Bundles) != 2 || bundleManager.Bundles["other.css"] == nil || bundleManager.Bundles["code.css"] == nil {
+		t.Errorf("Failed to replace manifest bundles on reload: %v", bundleManager.Bundles)
+	}
+
+	if err := ioutil.WriteFile(manifest, []byte(`[ { "Name": "code.css" } ]`), 0644); err != nil {
+		t.Fatalf("Failed to update manifest: %s", err)
+	}
+
+	if err := bundleManager.LoadManifest(manifest); err == nil {
+		t.Error("Failed to prevent manifest from replacing a created bundle")
+	}
+}
+
+// TestNewBundleManagerFromConfig ensures that bundles are loaded from the configuration section
+func TestNewBundleManagerFromConfig(t *testing.T) {
+	config := mvcapp.NewConfigurationManager()
+	config.BundleManifest = "./_test_missing_bundles.json"
+	config.Bundles = []*mvcapp.BundleDefinition{
+		{Name: "site.js", MimeType: "text/javascript", Files: []string{"static/**/*.js"}},
+	}
+
+	bundleManager := mvcapp.NewBundleManagerFromConfig(config)
+	if bundleManager.Bundles["site.js"] == nil || !bundleManager.Bundles["site.js"].Minify {
+		t.Error("Failed to load bundles from configuration")
+	}
+}
+
+// TestApplication_StopWatchingManifest ensures that the manifest is no longer reloaded once the
+// application is stopped
+func TestApplication_StopWatchingManifest(t *testing.T) {
+	manifest := fmt.Sprintf("%s/_test_watched_bundles.json", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(manifest)
+
+	if err := ioutil.WriteFile(manifest, []byte(`[ { "Name": "a.css", "MimeType": "text/css", "Files": [ "a.css" ] } ]`), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	config := mvcapp.NewConfigurationManager()
+	config.BundleManifest = "./_test_watched_bundles.json"
+	config.TaskDuration = 1
+
+	app := mvcapp.NewApplicationFromConfig(config)
+	if err := app.Stop(); err != nil {
+		t.Fatalf("Failed to stop application: %s", err)
+	}
+
+	data := `[ { "Name": "a.css", "MimeType": "text/css", "Files": [ "a.css" ] }, { "Name": "b.css", "MimeType": "text/css", "Files": [ "b.css" ] } ]`
+	if err := ioutil.WriteFile(manifest, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to update manifest: %s", err)
+	}
+
+	future := time.Now().Add(time.Hour)
+	os.Chtimes(manifest, future, future)
+
+	time.Sleep(1500 * time.Millisecond)
+	if app.RouteManager.BundleManager.Bundles["b.css"] != nil {
+		t.Error("Failed to stop watching the bundle manifest")
+	}
+}
+
+// TestBundleManager_WatchManifest ensures that bundles can be created and removed while the
+// manifest watcher reloads the manifest
+func TestBundleManager_WatchManifest(t *testing.T) {
+	manifest := fmt.Sprintf("%s/_test_reloaded_bundles.json", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(manifest)
+
+	if err := ioutil.WriteFile(manifest, []byte(`[ { "Name": "a.css", "MimeType": "text/css", "Files": [ "a.css" ] } ]`), 0644); err != nil {
+		t.Fatalf("Failed to write manifest: %s", err)
+	}
+
+	bundleManager := mvcapp.NewBundleManager()
+	if err := bundleManager.LoadManifest(manifest); err != nil {
+		t.Fatalf("Failed to load manifest: %s", err)
+	}
+
+	bundleManager.WatchManifest(time.Millisecond)
+	defer bundleManager.StopWatchingManifest()
+
+	for i := 0; i < 50; i++ {
+		modified := time.Now().Add(time.Duration(i+1) * time.Minute)
+		os.Chtimes(manifest, modified, modified)
+
+		name := fmt.Sprintf("created%d.css", i)
+		if err := bundleManager.CreateBundle(name, "text/css", []string{"created.css"}); err != nil {
+			t.Fatalf("Failed to create bundle while watching the manifest: %s", err)
+		}
+
+		if err := bundleManager.RemoveBundle(name); err != nil {
+			t.Fatalf("Failed to remove bundle while watching the manifest: %s", err)
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
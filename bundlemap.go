@@ -1,36 +1,322 @@
-/*
-	Digivance MVC Application Framework
-	Bundle Map Object
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines a bundle map object used by the bundle manager system
-*/
-
-package mvcapp
-
-import "time"
-
-// BundleMap represents the slice of filenames, the mime type and last build
-// date & time of a "Content Bundle"
-type BundleMap struct {
-	// Files is a slice of the full path and file name of the files to include
-	// in this content bundle
-	Files []string
-
-	// MimeType is the MIME type of the files in this bundle and is used to execute
-	// the appropriate minification methods in the bundle manager
-	MimeType string
-
-	// BuildDate is the time and date when this bundle was created, the bundle
-	// manager uses this to determine if a rebuild is warranted
-	BuildDate time.Time
-}
-
-// NewBundleMap returns a new BundleMap from the provided mime type and filename slice
-func NewBundleMap(mimeType string, files []string) *BundleMap {
-	return &BundleMap{
-		Files:     files,
-		MimeType:  mimeType,
-		BuildDate: time.Time{},
-	}
-}
+/*
+	Digivance MVC Application Framework
+	Bundle Map Object
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines a bundle map object used by the bundle manager system
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BundleMap represents the slice of filenames, the mime type and last build
+// date & time of a "Content Bundle"
+type BundleMap struct {
+	// Files is a slice of the files to include in this content bundle. Entries may be
+	// filenames, directories (all files within, recursively) or glob patterns where **
+	// matches any number of folders (E.g. "static/js/**/*.js"). Entries beginning with
+	// ! exclude the matching files (E.g. "!static/js/**/*.min.js"). Relative entries are
+	// resolved from the application path
+	Files []string
+
+	// Output is the optional path and filename the bundle is built to, relative to the
+	// application path (defaults to bundle/bundleName)
+	Output string
+
+	// Minify determines if the bundle content is minified when built (defaults to true)
+	Minify bool
+
+	// MinifyParams are optional parameters passed to the minifier of this bundle
+	MinifyParams map[string]string
+
+	// SourceMap determines if a version 3 source map is written next to the bundle (as
+	// bundleName.map) and referenced by a sourceMappingURL comment at the end of the bundle and
+	// the SourceMap header when served (defaults to true)
+	SourceMap bool
+
+	// Order is an optional slice of patterns that moves the matching files to the front of
+	// the bundle, in the order of the patterns (E.g. "**/vendor/**" for vendor first). Files
+	// not matching an order pattern keep their position after the ordered files
+	Order []string
+
+	// SourceFiles is the resolved list of full path and file names used by the last build,
+	// the bundle manager rebuilds the bundle when files are added or removed
+	SourceFiles []string `json:"-"`
+
+	// MimeType is the MIME type of the files in this bundle and is used to execute
+	// the appropriate minification methods in the bundle manager
+	MimeType string
+
+	// BuildDate is the time and date when this bundle was created, the bundle
+	// manager uses this to determine if a rebuild is warranted
+	BuildDate time.Time `json:"-"`
+
+	// Hash is the hex encoded SHA-256 hash of the built bundle content, used as the
+	// strong ETag and (shortened) as the fingerprint in the bundle url
+	Hash string `json:"-"`
+
+	// Integrity is the SHA-384 subresource integrity hash (E.g. "sha384-...") of the built
+	// bundle content, added to the integrity attribute of the bundle tags
+	Integrity string `json:"-"`
+
+	// CDNURL is an optional external url that serves a copy of this bundle, the bundle tag then
+	// includes the CDN copy and falls back to the local bundle if it fails its integrity check
+	CDNURL string
+
+	// artifacts are the built bundle files (keyed by filename suffix) when the bundle manager
+	// keeps bundles in memory
+	artifacts map[string][]byte
+}
+
+// Fingerprint returns the shortened content hash used in the url of this bundle
+func (bundleMap *BundleMap) Fingerprint() string {
+	if len(bundleMap.Hash) > 16 {
+		return bundleMap.Hash[:16]
+	}
+
+	return bundleMap.Hash
+}
+
+// ETag returns the strong entity tag of the built bundle content
+func (bundleMap *BundleMap) ETag() string {
+	return fmt.Sprintf("\"%s\"", bundleMap.Hash)
+}
+
+// hasMeta returns true if the provided pattern contains glob special characters
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// relativeBundlePath returns the provided full filename relative to the application path,
+// using forward slashes so that it can be compared against bundle patterns
+func relativeBundlePath(filename string) string {
+	filename = filepath.ToSlash(filename)
+	appPath := filepath.ToSlash(GetApplicationPath()) + "/"
+	return strings.TrimPrefix(filename, appPath)
+}
+
+// cleanBundlePattern returns the provided pattern relative to the application path
+func cleanBundlePattern(pattern string) string {
+	pattern = relativeBundlePath(pattern)
+	return strings.TrimPrefix(strings.TrimPrefix(pattern, "~/"), "./")
+}
+
+// MatchBundlePattern returns true if the provided relative filename matches the pattern,
+// supporting ** to match any number of folders (including none)
+func MatchBundlePattern(pattern string, filename string) bool {
+	return matchSegments(strings.Split(cleanBundlePattern(pattern), "/"), strings.Split(filename, "/"))
+}
+
+// matchSegments is the recursive path segment matcher used by MatchBundlePattern
+func matchSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) <= 0 {
+			return false
+		}
+
+		if ok, err := path.Match(pattern[0], name[0]); !ok || err != nil {
+			return false
+		}
+
+		pattern = pattern[1:]
+		name = name[1:]
+	}
+
+	return len(name) <= 0
+}
+
+// expandBundleEntry returns the full path and filenames described by a single Files entry. Hidden
+// folders and the provided outputs (the built bundles, see BundleManager.bundleOutputs) are skipped
+// when walking glob patterns
+func expandBundleEntry(entry string, outputs map[string]bool) ([]string, error) {
+	pattern := cleanBundlePattern(entry)
+	if !hasMeta(pattern) {
+		filename := bundleSourcePath(pattern)
+		si, err := statFile(filename)
+		if err != nil || !si.IsDir() {
+			// Missing files are returned as is so that the build reports them
+			return []string{filename}, nil
+		}
+
+		pattern = strings.TrimRight(pattern, "/") + "/**"
+	}
+
+	// Walk from the deepest folder that doesn't contain any glob characters
+	segments := strings.Split(pattern, "/")
+	base := []string{}
+	for _, segment := range segments {
+		if hasMeta(segment) {
+			break
+		}
+
+		base = append(base, segment)
+	}
+
+	root := GetApplicationPath()
+	if len(base) > 0 {
+		root = fmt.Sprintf("%s/%s", root, strings.Join(base, "/"))
+	}
+
+	rtn := []string{}
+	err := walkFiles(root, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && filename == root {
+				return filepath.SkipDir
+			}
+
+			return err
+		}
+
+		if info.IsDir() {
+			if filename != root && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !outputs[filepath.Clean(filename)] && MatchBundlePattern(pattern, relativeBundlePath(filename)) {
+			rtn = append(rtn, filename)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("Failed to resolve bundle files for %s: %s", entry, err)
+	}
+
+	return rtn, nil
+}
+
+// ResolveFiles expands the Files entries (filenames, directories and glob patterns) into the
+// full path and file names of this bundle, removing excluded files and applying the Order rules.
+// The file system is read each time this is called so that new files are picked up
+func (bundleMap *BundleMap) ResolveFiles() ([]string, error) {
+	return bundleMap.resolveFiles(nil)
+}
+
+// resolveFiles is the implementation of ResolveFiles, leaving the provided outputs out of the
+// glob patterns
+func (bundleMap *BundleMap) resolveFiles(outputs map[string]bool) ([]string, error) {
+	rtn := []string{}
+	found := make(map[string]bool, 0)
+	excludes := []string{}
+
+	for _, entry := range bundleMap.Files {
+		if strings.HasPrefix(entry, "!") {
+			excludes = append(excludes, entry[1:])
+			continue
+		}
+
+		files, err := expandBundleEntry(entry, outputs)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, filename := range files {
+			if !found[filename] {
+				found[filename] = true
+				rtn = append(rtn, filename)
+			}
+		}
+	}
+
+	if len(excludes) > 0 {
+		included := []string{}
+		for _, filename := range rtn {
+			excluded := false
+			for _, exclude := range excludes {
+				if MatchBundlePattern(exclude, relativeBundlePath(filename)) {
+					excluded = true
+					break
+				}
+			}
+
+			if !excluded {
+				included = append(included, filename)
+			}
+		}
+
+		rtn = included
+	}
+
+	if len(bundleMap.Order) > 0 {
+		ordered := []string{}
+		placed := make(map[string]bool, 0)
+		for _, order := range bundleMap.Order {
+			for _, filename := range rtn {
+				if !placed[filename] && MatchBundlePattern(order, relativeBundlePath(filename)) {
+					placed[filename] = true
+					ordered = append(ordered, filename)
+				}
+			}
+		}
+
+		for _, filename := range rtn {
+			if !placed[filename] {
+				ordered = append(ordered, filename)
+			}
+		}
+
+		rtn = ordered
+	}
+
+	return rtn, nil
+}
+
+// filesChanged returns true if the provided resolved files differ from the last build
+func (bundleMap *BundleMap) filesChanged(files []string) bool {
+	if len(files) != len(bundleMap.SourceFiles) {
+		return true
+	}
+
+	for i := range files {
+		if files[i] != bundleMap.SourceFiles[i] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// OutputFilename returns the full path and filename this bundle is built to
+func (bundleMap *BundleMap) OutputFilename(bundleName string) string {
+	if bundleMap.Output != "" {
+		return bundleSourcePath(cleanBundlePattern(bundleMap.Output))
+	}
+
+	return fmt.Sprintf("%s/bundle/%s", GetApplicationPath(), bundleName)
+}
+
+// NewBundleMap returns a new BundleMap from the provided mime type and filename slice
+func NewBundleMap(mimeType string, files []string) *BundleMap {
+	return &BundleMap{
+		Files:        files,
+		Order:        []string{},
+		MimeType:     mimeType,
+		Minify:       true,
+		MinifyParams: map[string]string{},
+		SourceMap:    true,
+		BuildDate:    time.Time{},
+		SourceFiles:  []string{},
+	}
+}
@@ -0,0 +1,122 @@
+/*
+	Digivance MVC Application Framework
+	Command Line Tool
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the mvcapp command line tool. The check command parses every template in the
+	views folder of a site and reports syntax errors, unknown functions, missing partial views,
+	view components without a views/components folder and undefined templates, along with View,
+	StreamView and SimpleView calls in the Go source whose templates can't be found. The command
+	exits with a non zero status when problems were found, so it can be used in build scripts:
+
+		mvcapp check [-source path] [-funcs name,name] [site path]
+
+	The site path (default the current folder) is the folder that holds the views folder. Template
+	functions registered by the application (see TemplateFuncs.Register) aren't known to the tool
+	and are declared with -funcs, or the application can call mvcapp.CheckViews itself.
+*/
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/digivance/mvcapp"
+)
+
+// usage prints the usage of the tool
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: mvcapp check [-source path] [-funcs name,name] [site path]")
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] != "check" {
+		usage()
+		os.Exit(2)
+	}
+
+	os.Exit(check(os.Args[2:]))
+}
+
+// check runs the check command with the provided arguments and returns the exit status
+func check(args []string) int {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	source := flags.String("source", "", "folder of the Go source to check for View calls (default the site path)")
+	funcs := flags.String("funcs", "", "comma separated names of the template functions registered by the application")
+	flags.Usage = usage
+	flags.Parse(args)
+
+	sitePath := "."
+	if flags.NArg() > 0 {
+		sitePath = flags.Arg(0)
+	}
+
+	sitePath, err := filepath.Abs(sitePath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if *source == "" {
+		*source = sitePath
+	}
+
+	// The site is read through the application file system, so that templates resolve exactly
+	// as they do at runtime (see MakeTemplateList)
+	mvcapp.UseFileSystem(os.DirFS(sitePath), false)
+
+	funcMap := template.FuncMap{}
+	for _, name := range strings.Split(*funcs, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			funcMap[name] = func(args ...interface{}) interface{} { return nil }
+		}
+	}
+
+	issues, err := mvcapp.CheckViews(funcMap)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	references, err := mvcapp.CheckViewReferences(*source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	issues = append(issues, references...)
+	for _, issue := range issues {
+		fmt.Println(relativeIssue(sitePath, mvcapp.GetApplicationPath(), issue))
+	}
+
+	if len(issues) > 0 {
+		fmt.Printf("%d problem(s) found\n", len(issues))
+		return 1
+	}
+
+	fmt.Println("No problems found")
+	return 0
+}
+
+// relativeIssue returns the issue with its filename relative to the current folder, view
+// filenames are reported against the application path and are mapped to the site path first
+func relativeIssue(sitePath string, appPath string, issue mvcapp.ViewIssue) string {
+	filename := issue.Filename
+	if strings.HasPrefix(filepath.ToSlash(filename), filepath.ToSlash(appPath)+"/") {
+		filename = filepath.Join(sitePath, filename[len(appPath)+1:])
+	}
+
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, filename); err == nil {
+			filename = rel
+		}
+	}
+
+	issue.Filename = filename
+	return issue.String()
+}
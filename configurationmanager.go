@@ -1,145 +1,298 @@
-/*
-	Digivance MVC Application Framework
-	Configuration Manager Object
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines the general application settings and configurations system. This system
-	allows the package caller to easily interact with all the configurable settings as well as
-	read / write them to human readable json configuration files
-*/
-
-package mvcapp
-
-import (
-	"encoding/json"
-	"io/ioutil"
-	"strings"
-)
-
-// ConfigurationManager is a simple json serializer wrapper that allows an
-// mvcapp to easily save and load human friendly json files for application
-// configuration values
-type ConfigurationManager struct {
-	// AppName is the display name for this application wherever applicable
-	AppName string
-
-	// AppVersion is the display version of this application wherever applicable
-	AppVersion string
-
-	// DomainName is the FQDN for this application
-	DomainName string
-
-	// BindAddress is the TCP/IP address to bind the listener to (normally leave blank for all)
-	BindAddress string
-
-	// HTTPPort is the TCP/IP Port number to listen on for plain http
-	HTTPPort int
-
-	// HTTPSPort is the TCP/IP Port number to listen on for TLS http
-	HTTPSPort int
-
-	// LogFilename is the full path and filename to write logging messages to (based on log level)
-	LogFilename string
-
-	// LogLevel represents the level of messages that are written to the log file. Allows for caller
-	// to switch at runtime based on conditions or needs
-	LogLevel int
-
-	// TLSCertFile is the full path and filename of the TLS Certificate file to use for HTTPS
-	TLSCertFile string
-
-	// TLSKeyFile is the full path and filename of the TLS Key file to use for HTTPS
-	TLSKeyFile string
-
-	// AllowGoogleAuthFiles will allow the app to serve google site authentication files over plain
-	// http even if the app is forcing all traffic to https (normally irrelevent)
-	AllowGoogleAuthFiles bool
-
-	// HTTPSessionIDKey is the name of the cookie that will store our user's session id if using
-	// the built in http session manager system
-	HTTPSessionIDKey string
-
-	// HTTPSessionTimeout is the number of minutes that a users http session data will be stored in
-	// memory between requests
-	HTTPSessionTimeout int64
-
-	// TaskDuration is the number of seconds to idle between evaluating internal tasks (such as cleaning
-	// user http sessions in memory)
-	TaskDuration int64
-
-	// DefaultController is used to define where requests to the root of this DomainName are routed
-	// Should be Home in most cases
-	DefaultController string
-
-	// DefaultAction is used to define the default action method to be executed if unable to map the
-	// the requested action. Should be Index in most cases
-	DefaultAction string
-}
-
-// NewConfigurationManager returns an empty new Configuration Manager struct
-func NewConfigurationManager() *ConfigurationManager {
-	return &ConfigurationManager{
-		AppName:    "MyApp",
-		AppVersion: "0.0.0",
-
-		DomainName:  "domain.tld",
-		BindAddress: "",
-		HTTPPort:    80,
-		HTTPSPort:   443,
-
-		LogFilename: "./app.log",
-		LogLevel:    2,
-
-		TLSCertFile: "",
-		TLSKeyFile:  "",
-
-		AllowGoogleAuthFiles: true,
-
-		HTTPSessionIDKey:   "mvcapp.sessionid",
-		HTTPSessionTimeout: 30,
-		TaskDuration:       60,
-
-		DefaultController: "Home",
-		DefaultAction:     "Index",
-	}
-}
-
-// NewConfigurationManagerFromFile returns a new Configuration Manager struct
-// constructed from the values in the provided json file
-func NewConfigurationManagerFromFile(filename string) (*ConfigurationManager, error) {
-	if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
-		filename = GetApplicationPath() + filename[1:]
-	}
-
-	data, err := ioutil.ReadFile(filename)
-	if err != nil {
-		return nil, err
-	}
-
-	config := NewConfigurationManager()
-	err = json.Unmarshal(data, config)
-	if err != nil {
-		return nil, err
-	}
-
-	return config, nil
-}
-
-// SaveFile will save the current values to a human readable json configuration file
-func (config *ConfigurationManager) SaveFile(filename string) error {
-	if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
-		filename = GetApplicationPath() + filename[1:]
-	}
-
-	data, err := json.MarshalIndent(config, "", "    ")
-	if err != nil {
-		return err
-	}
-
-	err = ioutil.WriteFile(filename, data, 0644)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
+/*
+	Digivance MVC Application Framework
+	Configuration Manager Object
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the general application settings and configurations system. This system
+	allows the package caller to easily interact with all the configurable settings as well as
+	read / write them to human readable json configuration files
+*/
+
+package mvcapp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+)
+
+// ConfigurationManager is a simple json serializer wrapper that allows an
+// mvcapp to easily save and load human friendly json files for application
+// configuration values
+type ConfigurationManager struct {
+	// AppName is the display name for this application wherever applicable
+	AppName string
+
+	// AppVersion is the display version of this application wherever applicable
+	AppVersion string
+
+	// DomainName is the FQDN for this application
+	DomainName string
+
+	// BindAddress is the TCP/IP address to bind the listener to (normally leave blank for all)
+	BindAddress string
+
+	// HTTPPort is the TCP/IP Port number to listen on for plain http
+	HTTPPort int
+
+	// HTTPSPort is the TCP/IP Port number to listen on for TLS http
+	HTTPSPort int
+
+	// LogFilename is the full path and filename to write logging messages to (based on log level)
+	LogFilename string
+
+	// LogLevel represents the level of messages that are written to the log file. Allows for caller
+	// to switch at runtime based on conditions or needs
+	LogLevel int
+
+	// Listeners is an optional collection of additional addresses (tcp, unix sockets or systemd
+	// activated sockets) that the application serves when started with RunListeners
+	Listeners []*Listener
+
+	// TLSCertFile is the full path and filename of the TLS Certificate file to use for HTTPS
+	TLSCertFile string
+
+	// TLSKeyFile is the full path and filename of the TLS Key file to use for HTTPS
+	TLSKeyFile string
+
+	// TrustedProxies is the list of reverse proxy networks (CIDR or IP addresses) whose Forwarded
+	// and X-Forwarded-* headers are honored when determining the client address, scheme and host
+	TrustedProxies []string
+
+	// CanonicalRedirect will redirect requests to the DomainName (rather than the requested host)
+	// when forcing secure or enforcing the WWWPolicy
+	CanonicalRedirect bool
+
+	// WWWPolicy defines the canonical form of the host name, "www" to force the www. prefix,
+	// "non-www" to strip it, or blank to leave the host as requested
+	WWWPolicy string
+
+	// HTTPSRedirectStatus is the http status code used when redirecting to https or to the canonical
+	// host (E.g. 301 or 308 for permanent, 302 or 307 for temporary)
+	HTTPSRedirectStatus int
+
+	// HSTSMaxAge is the number of seconds browsers should remember to only use https for this site,
+	// the Strict-Transport-Security header is not sent on https responses when 0
+	HSTSMaxAge int64
+
+	// HSTSIncludeSubDomains adds the includeSubDomains directive to the Strict-Transport-Security header
+	HSTSIncludeSubDomains bool
+
+	// HSTSPreload adds the preload directive to the Strict-Transport-Security header
+	HSTSPreload bool
+
+	// ACMEDirectoryURL is the directory endpoint of the ACME (RFC 8555) certificate authority used
+	// by RunForcedSecureACME. Point this at a local stand in (such as Pebble) for testing
+	ACMEDirectoryURL string
+
+	// ACMEDirectoryCAFile is an optional PEM file of CA certificates to trust when connecting to
+	// the ACME directory (E.g. pebble.minica.pem when testing against Pebble)
+	ACMEDirectoryCAFile string
+
+	// ACMEEmail is the contact email address registered with the ACME account
+	ACMEEmail string
+
+	// ACMEDomains is the list of domain names to request a certificate for (DomainName if empty)
+	ACMEDomains []string
+
+	// ACMECertPath is the folder where the ACME account key and issued certificates are stored
+	ACMECertPath string
+
+	// ACMERenewDays is the number of days before expiration that ACME certificates are renewed
+	ACMERenewDays int
+
+	// DevelopmentMode enables the development features of the framework (such as BundleDevelopmentMode)
+	// and disables production optimizations (such as MinifyViews). Leave false in production
+	DevelopmentMode bool
+
+	// MinifyViews minifies the html, json and svg output of controller results (see ViewMinifier),
+	// controllers can opt in or out individually with their MinifyOutput member
+	MinifyViews bool
+
+	// WatchInterval is the number of milliseconds between polls of the watched bundle, view and
+	// configuration files in DevelopmentMode (see Watcher)
+	WatchInterval int64
+
+	// WatchDebounce is the number of milliseconds the watched files must remain unchanged before
+	// the change is acted upon, so that a burst of saves results in a single rebuild
+	WatchDebounce int64
+
+	// BundleDevelopmentMode serves content bundles as their individual, unminified source files and
+	// rebuilds bundles on request when a source file has changed. Leave false in production
+	BundleDevelopmentMode bool
+
+	// BundleInMemory keeps built content bundles in memory instead of writing them to the bundle
+	// folder (for read only file systems and containers)
+	BundleInMemory bool
+
+	// BundleMaxParallelBuilds is the number of content bundles built at once (0 for the number of CPUs)
+	BundleMaxParallelBuilds int
+
+	// BundleManifest is the path and filename of the json bundle manifest (see BundleDefinition)
+	// that is loaded, and watched for changes, when it exists
+	BundleManifest string
+
+	// Bundles is an optional list of content bundle definitions, for declaring bundles directly in
+	// the configuration file rather than a separate BundleManifest
+	Bundles []*BundleDefinition
+
+	// AllowGoogleAuthFiles will allow the app to serve google site authentication files over plain
+	// http even if the app is forcing all traffic to https (normally irrelevent)
+	AllowGoogleAuthFiles bool
+
+	// HTTPSessionIDKey is the name of the cookie that will store our user's session id if using
+	// the built in http session manager system
+	HTTPSessionIDKey string
+
+	// HTTPSessionTimeout is the number of minutes that a users http session data will be stored in
+	// memory between requests
+	HTTPSessionTimeout int64
+
+	// RestartTimeout is the number of seconds to wait for a restarted process to become ready, and
+	// for in flight requests to drain afterwards (see Application.Restart)
+	RestartTimeout int64
+
+	// TaskDuration is the number of seconds to idle between evaluating internal tasks (such as cleaning
+	// user http sessions in memory)
+	TaskDuration int64
+
+	// DefaultController is used to define where requests to the root of this DomainName are routed
+	// Should be Home in most cases
+	DefaultController string
+
+	// DefaultAction is used to define the default action method to be executed if unable to map the
+	// the requested action. Should be Index in most cases
+	DefaultAction string
+
+	// DefaultLayout is the name of the layout template that wraps the views of controllers that
+	// don't set their own Layout, found using the view folders (E.g. "_layout.htm")
+	DefaultLayout string
+
+	// Cultures are the cultures supported by the application (E.g. "en-US", "fr"), the first
+	// being the default culture. Localization is disabled when empty
+	Cultures []string
+
+	// LocalesPath is the folder of the message catalogs, named after their culture (E.g.
+	// "./locales/fr.json" or "./locales/fr.po")
+	LocalesPath string
+
+	// CultureCookieName is the name of the cookie that holds the users culture
+	CultureCookieName string
+
+	// CultureQueryKey is the query string key that selects the culture (E.g. ?culture=fr)
+	CultureQueryKey string
+
+	// filename is the full path and filename this configuration was loaded from (if any)
+	filename string
+}
+
+// NewConfigurationManager returns an empty new Configuration Manager struct
+func NewConfigurationManager() *ConfigurationManager {
+	return &ConfigurationManager{
+		AppName:    "MyApp",
+		AppVersion: "0.0.0",
+
+		DomainName:  "domain.tld",
+		BindAddress: "",
+		HTTPPort:    80,
+		HTTPSPort:   443,
+
+		LogFilename: "./app.log",
+		LogLevel:    2,
+
+		TLSCertFile: "",
+		TLSKeyFile:  "",
+
+		Listeners: []*Listener{},
+
+		TrustedProxies: []string{},
+
+		CanonicalRedirect:     false,
+		WWWPolicy:             "",
+		HTTPSRedirectStatus:   307,
+		HSTSMaxAge:            0,
+		HSTSIncludeSubDomains: false,
+		HSTSPreload:           false,
+
+		ACMEDirectoryURL:    "https://acme-v02.api.letsencrypt.org/directory",
+		ACMEDirectoryCAFile: "",
+		ACMEEmail:           "",
+		ACMEDomains:         []string{},
+		ACMECertPath:        "./certs",
+		ACMERenewDays:       30,
+
+		DevelopmentMode: false,
+		MinifyViews:     false,
+		WatchInterval:   500,
+		WatchDebounce:   100,
+
+		BundleDevelopmentMode:   false,
+		BundleInMemory:          false,
+		BundleMaxParallelBuilds: 0,
+		BundleManifest:          "./bundles.json",
+		Bundles:                 []*BundleDefinition{},
+
+		AllowGoogleAuthFiles: true,
+
+		HTTPSessionIDKey:   "mvcapp.sessionid",
+		HTTPSessionTimeout: 30,
+		TaskDuration:       60,
+		RestartTimeout:     30,
+
+		DefaultController: "Home",
+		DefaultAction:     "Index",
+		DefaultLayout:     "",
+
+		Cultures:          []string{},
+		LocalesPath:       "./locales",
+		CultureCookieName: "mvcapp.culture",
+		CultureQueryKey:   "culture",
+	}
+}
+
+// NewConfigurationManagerFromFile returns a new Configuration Manager struct
+// constructed from the values in the provided json file
+func NewConfigurationManagerFromFile(filename string) (*ConfigurationManager, error) {
+	if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
+		filename = GetApplicationPath() + filename[1:]
+	}
+
+	data, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	config := NewConfigurationManager()
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		return nil, err
+	}
+
+	config.filename = filename
+	return config, nil
+}
+
+// Filename returns the full path and filename this configuration was loaded from, or an empty
+// string if it was not loaded from a file
+func (config *ConfigurationManager) Filename() string {
+	return config.filename
+}
+
+// SaveFile will save the current values to a human readable json configuration file
+func (config *ConfigurationManager) SaveFile(filename string) error {
+	if strings.HasPrefix(filename, "~/") || strings.HasPrefix(filename, "./") {
+		filename = GetApplicationPath() + filename[1:]
+	}
+
+	data, err := json.MarshalIndent(config, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(filename, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
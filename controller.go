@@ -1,366 +1,635 @@
-/*
-	Digivance MVC Application Framework
-	Base Controller Features
-	Dan Mayor (dmayor@digivance.com)
-
-	This file defines the base controller functionality that the caller will use to derrive
-	their custom controller objects.
-*/
-
-package mvcapp
-
-import (
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
-	"time"
-)
-
-// ControllerCallback is a simple declaration to provide a callback method
-// members (e.g. variables that point to methods to be executed)
-type ControllerCallback func()
-
-// ErrorResultCallback is a simple declaration to provide a callback method
-// used when there is an internal server error (such as custom error page)
-type ErrorResultCallback func(err error) *ActionResult
-
-// NotFoundResultCallback is a simple declaration to provide a callback method
-// used when the requested content can not be found (custom 404)
-type NotFoundResultCallback func() *ActionResult
-
-// IController defines the RegisterAction and Execute methods that
-// need to be implemented by all controllers
-type IController interface {
-	// RegisterAction should add the Verb, Controller and Action method names to the
-	// base controllers action map
-	RegisterAction(string, string, ActionMethod)
-
-	// Execute should query and execute the mapped action method
-	Execute() (*ActionResult, error)
-
-	// WriteResponse should write the provided action result to the response stream
-	WriteResponse(*ActionResult) error
-
-	// RedirectJS is a method that should write an html page with javascript redirect
-	// function directly to the response stream (this can be used to lock pages from
-	// users based of custom conditions, such as logged in or not)
-	RedirectJS(string) error
-
-	// ToController should return the actual base controller object (so that the system
-	// can interact with controller variable members)
-	ToController() *Controller
-}
-
-// Controller contains the basic members shared by custom controllers,
-// also defines the required RegisterAction and Execute methods (below)
-type Controller struct {
-	// IController is the interface that the base controller implements
-	IController
-
-	// Request is the http.Request object that this controller is responding to
-	Request *http.Request
-
-	// Response is the response writer stream to write data to the client
-	Response http.ResponseWriter
-
-	// BundleManager is a pointer to the content bundle manager used by the route manager
-	BundleManager *BundleManager
-
-	// Session is the User browser session data collection for the user who made this request
-	Session *Session
-
-	// Cookies are populated from the collection submitted from the client. Server can alter
-	// or add cookies to this collection to have them delivered back to the client. (Call the
-	// controllers DeleteCookie method to signal the client to forget a cookie)
-	Cookies []*http.Cookie
-
-	// ContinuePipeline is used from methods that mean to prevent the execution of a controller
-	// action method. E.g. if a controller signals to RedirectJS in the BeforeExecute callback,
-	// this will be set to false and will prevent the action method from assembling and executing
-	// the action result.
-	ContinuePipeline bool
-
-	// ControllerName is used to help the template path mapping of the View method of this controller.
-	// Should be set in the controller creator method and should represent the folder name where the
-	// views for this controller can be found
-	ControllerName string
-
-	// RequestedPath is a quick reference member that contains the url path (after the domain) that
-	// was requested.
-	RequestedPath string
-
-	// QueryString is a quick reference member that contains the key value pair query string parameters
-	// that were submitted with this request
-	QueryString map[string]string
-
-	// Fragment represents the #Value portion of the requested URL (Note some sources indicate that URL
-	// fragments are or should be depreciated. Use URL fragments and this member at your discretion)
-	Fragment string
-
-	// DefaultAction is the name of the default action method name (in the route map) to try and execute
-	// when making a request directly to the controller.  (E.g. site.com/controller) (This should be your
-	// Index, Home or Default page name)
-	DefaultAction string
-
-	// ActionRoutes stores the routes which have been registered for this controller. This collection is
-	// used in the Execute method to find the appropriate action method function to call
-	ActionRoutes []*ActionMap
-
-	// ViewData is the preferred means of pasing data models to your views as of version 0.2.0.
-	ViewData map[string]interface{}
-
-	// BeforeExecute is a callback method that a controller can set to provide a global method called before
-	// the action method is executed. (Controller global prep function)
-	BeforeExecute ControllerCallback
-
-	// AfterExecute is a callback method that a controller can set to provide a global method called after
-	// the action method is executed. (Controller global clean up function)
-	AfterExecute ControllerCallback
-
-	// ErrorResult is a callback method that a controller can set to respond to error conditions with a custom
-	// error page
-	ErrorResult ErrorResultCallback
-
-	// NotFoundResult is a callback method that a controller can set to respond to a content not found condition
-	// with a custom 404 page
-	NotFoundResult NotFoundResultCallback
-}
-
-// NewBaseController returns a reference to a new Base Controller
-func NewBaseController(request *http.Request) *Controller {
-	controllerName := strings.Split(request.URL.Path, "/")[0]
-	if controllerName == "" {
-		controllerName = "Home"
-	}
-
-	rtn := &Controller{
-		Request:          request,
-		Session:          NewSession(),
-		Cookies:          make([]*http.Cookie, 0),
-		ContinuePipeline: true,
-
-		ControllerName: controllerName,
-		RequestedPath:  request.URL.Path,
-		QueryString:    map[string]string{},
-		Fragment:       "",
-
-		DefaultAction: "",
-		ActionRoutes:  make([]*ActionMap, 0),
-		ViewData:      make(map[string]interface{}, 0),
-	}
-
-	for _, cookie := range request.Cookies() {
-		rtn.Cookies = append(rtn.Cookies, cookie)
-	}
-
-	return rtn
-}
-
-// RegisterAction allows package caller to map a controller action method to
-// a given Http Request verb and action name (E.g. site.com/Controller/ActionName)
-func (controller *Controller) RegisterAction(verb string, name string, method ActionMethod) {
-	controller.ActionRoutes = append(controller.ActionRoutes, NewActionMap(verb, name, method))
-}
-
-// GetCookie returns the requested cookie from this controllers collection
-func (controller *Controller) GetCookie(name string) *http.Cookie {
-	for _, v := range controller.Cookies {
-		if strings.EqualFold(v.Name, name) {
-			return v
-		}
-	}
-
-	return nil
-}
-
-// SetCookie will overwrite or create a cookie in this controllers collection
-func (controller *Controller) SetCookie(cookie *http.Cookie) {
-	for k, v := range controller.Cookies {
-		if strings.EqualFold(v.Name, cookie.Name) {
-			controller.Cookies[k] = cookie
-			return
-		}
-	}
-
-	controller.Cookies = append(controller.Cookies, cookie)
-}
-
-// DeleteCookie will set the cookie (identified by provided ccokieName) to expire in
-// the past, thus making the browser remove it and stop sending it back.
-func (controller *Controller) DeleteCookie(cookieName string) {
-	for _, v := range controller.Cookies {
-		if strings.EqualFold(v.Name, cookieName) {
-			v.Expires = time.Now().Add(-1 * time.Hour)
-		}
-	}
-}
-
-// Execute is called by the route manager instructing this controller to respond
-func (controller *Controller) Execute() (*ActionResult, error) {
-	verb := controller.Request.Method
-	actionName := controller.DefaultAction
-	params := []string{}
-
-	if strings.Contains(strings.ToLower(controller.RequestedPath), "/") && controller.RequestedPath != "/" {
-		// Strips the leading / so we prevent the empty first parts element below
-		url := controller.RequestedPath
-		if strings.HasPrefix(url, "/") {
-			url = url[1:]
-		}
-
-		parts := strings.Split(url, "/")
-
-		if len(parts) > 1 {
-			actionName = parts[1]
-
-			if len(parts) > 2 {
-				params = parts[2:]
-			}
-		}
-	}
-
-	for _, actionMethod := range controller.ActionRoutes {
-		if strings.EqualFold(actionMethod.Name, actionName) && (len(actionMethod.Verb) <= 0 || strings.EqualFold(actionMethod.Verb, verb)) {
-			if strings.EqualFold(verb, "POST") {
-				if err := controller.Request.ParseForm(); err != nil {
-					return nil, err
-				}
-			}
-
-			res := actionMethod.Method(params)
-			return res, nil
-		}
-	}
-
-	if controller.NotFoundResult != nil {
-		return controller.NotFoundResult(), nil
-	}
-
-	return controller.DefaultNotFoundPage(), nil
-}
-
-// WriteResponse is called from the route manager to execute the result that was constructed
-// from this controllers Execute method (E.g. the result returned from the action if mapped)
-func (controller *Controller) WriteResponse(result *ActionResult) error {
-	if controller.ContinuePipeline {
-		if result == nil || len(result.Data) <= 0 {
-			if controller.NotFoundResult != nil {
-				result = controller.NotFoundResult()
-			} else {
-				result = controller.DefaultNotFoundPage()
-			}
-		}
-
-		err := result.Execute(controller.Response)
-		return err
-	}
-
-	return nil
-}
-
-// RedirectJS is a helper method that will write a very simple html page using the
-// window.location.href='url' method to redirect the borwser to the provided url
-// Note this will also set the controller.ContinuePipeline to false, meaning that
-// the ActionMethod for this request will NOT be called. This allows us to use this
-// method from BeginExecute callbacks to lock down an entire controller to given
-// conditions, such as if the user is logged in. Can be called anytime before
-// AfterExecute.
-func (controller *Controller) RedirectJS(url string) error {
-	data := fmt.Sprintf("<html><head><title>Redirecting...</title><body><script type=\"text/javascript\">window.location.href='%s';</script></body></html>", url)
-
-	// We manually write the cookies to the browser here because we'll be breaking the
-	// standard pipelint (eg ContinuePipeline = false)
-	res := NewActionResult([]byte(data))
-	res.StatusCode = 200
-	res.Cookies = controller.Cookies
-	res.Headers["Cache-Control"] = "no-cache, no-store, must-revalidate"
-	res.Headers["Pragma"] = "no-cache"
-	res.Headers["Expires"] = "0"
-
-	if err := res.Execute(controller.Response); err != nil {
-		// Can't test this as it shouldn't be possible to fail here, more of a doomsday protection
-		return err
-	}
-
-	controller.ContinuePipeline = false
-	return nil
-}
-
-// Result returns a new ActionResult and automatically assigns the controllers cookies
-func (controller *Controller) Result(data []byte) *ActionResult {
-	res := NewActionResult(data)
-	res.Cookies = controller.Cookies
-	return res
-}
-
-// View will take the provided array of template names and try to make an mvcapp Template
-// List (see func mvcapp.MakeTemplateList) using the type name of this controller (from
-// reflection). Then returns the ViewResult that is created. Note this method does NOT
-// include the ViewData collection of the base controller
-func (controller *Controller) View(templates []string, model interface{}) *ActionResult {
-	templateList := MakeTemplateList(strings.ToLower(controller.ControllerName), templates)
-	res, err := NewViewResult(templateList, model)
-	if err != nil {
-		if controller.ErrorResult != nil {
-			return controller.ErrorResult(errors.New("Internal server error, failed to render page"))
-		}
-
-		return controller.DefaultErrorPage(errors.New("Internal server error, failed to render page"))
-	}
-
-	res.Cookies = controller.Cookies
-	return res
-}
-
-// SimpleView takes the provided variadic strings and uses them to call controller.View(templates, controller)
-// Note this is called at the base controller object, therefor will not accept custom controller members.
-// You can pass custom data models by setting them to the controller.ViewData map, which can be accessed in the
-// template via .ViewData["key"]
-func (controller *Controller) SimpleView(templates ...string) *ActionResult {
-	return controller.View(templates, controller)
-}
-
-// JSON returns a new JSONResult object of the provided payload
-func (controller *Controller) JSON(payload interface{}) *ActionResult {
-	res, err := NewJSONResult(payload)
-	if err != nil {
-		res = &ActionResult{
-			Data: []byte(fmt.Sprintf("{\"Success\":false,\"Error\":\"%s\"}", err)),
-		}
-	}
-
-	res.Cookies = controller.Cookies
-	return res
-}
-
-// ToController is a method defined by the controller object (which implements IController) that
-// returns a reference to the Controller object it is called on. We use this in the route manager
-// to gain access to the session and cookie collections of the base controller from a custom controller
-func (controller *Controller) ToController() *Controller {
-	return controller
-}
-
-// DefaultErrorPage will attempt to render the built in error page
-func (controller *Controller) DefaultErrorPage(err error) *ActionResult {
-	LogWarning(fmt.Sprintf("Servind default error page because: %s", err.Error()))
-	html := fmt.Sprintf("<html><head><title>Server Error</title></head><body><h1>Server Error :(</h1>%s</body></html>", err.Error())
-	data := []byte(html)
-	res := NewActionResult(data)
-	res.Cookies = controller.Cookies
-	res.StatusCode = 500
-
-	return res
-}
-
-// DefaultNotFoundPage will attempt to render the built in 404 page
-func (controller *Controller) DefaultNotFoundPage() *ActionResult {
-	LogWarning(fmt.Sprintf("Serving default not found page because: %s", controller.RequestedPath))
-	html := fmt.Sprintf("<html><head><title>Content Not Found</title></head><body><h1>Content Missing</h1>We're sorry, we could not find '%s' from this app :(</body></html>", controller.RequestedPath)
-	data := []byte(html)
-	res := NewActionResult(data)
-	res.Cookies = controller.Cookies
-	res.StatusCode = 404
-
-	return res
-}
+/*
+	Digivance MVC Application Framework
+	Base Controller Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the base controller functionality that the caller will use to derrive
+	their custom controller objects.
+*/
+
+package mvcapp
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ControllerCallback is a simple declaration to provide a callback method
+// members (e.g. variables that point to methods to be executed)
+type ControllerCallback func()
+
+// ErrorResultCallback is a simple declaration to provide a callback method
+// used when there is an internal server error (such as custom error page)
+type ErrorResultCallback func(err error) *ActionResult
+
+// NotFoundResultCallback is a simple declaration to provide a callback method
+// used when the requested content can not be found (custom 404)
+type NotFoundResultCallback func() *ActionResult
+
+// IController defines the RegisterAction and Execute methods that
+// need to be implemented by all controllers
+type IController interface {
+	// RegisterAction should add the Verb, Controller and Action method names to the
+	// base controllers action map
+	RegisterAction(string, string, ActionMethod)
+
+	// Execute should query and execute the mapped action method
+	Execute() (*ActionResult, error)
+
+	// WriteResponse should write the provided action result to the response stream
+	WriteResponse(*ActionResult) error
+
+	// RedirectJS is a method that should write an html page with javascript redirect
+	// function directly to the response stream (this can be used to lock pages from
+	// users based of custom conditions, such as logged in or not)
+	RedirectJS(string) error
+
+	// ToController should return the actual base controller object (so that the system
+	// can interact with controller variable members)
+	ToController() *Controller
+}
+
+// Controller contains the basic members shared by custom controllers,
+// also defines the required RegisterAction and Execute methods (below)
+type Controller struct {
+	// IController is the interface that the base controller implements
+	IController
+
+	// Request is the http.Request object that this controller is responding to
+	Request *http.Request
+
+	// Response is the response writer stream to write data to the client
+	Response http.ResponseWriter
+
+	// BundleManager is a pointer to the content bundle manager used by the route manager
+	BundleManager *BundleManager
+
+	// Session is the User browser session data collection for the user who made this request
+	Session *Session
+
+	// Cookies are populated from the collection submitted from the client. Server can alter
+	// or add cookies to this collection to have them delivered back to the client. (Call the
+	// controllers DeleteCookie method to signal the client to forget a cookie)
+	Cookies []*http.Cookie
+
+	// ContinuePipeline is used from methods that mean to prevent the execution of a controller
+	// action method. E.g. if a controller signals to RedirectJS in the BeforeExecute callback,
+	// this will be set to false and will prevent the action method from assembling and executing
+	// the action result.
+	ContinuePipeline bool
+
+	// ControllerName is used to help the template path mapping of the View method of this controller.
+	// Should be set in the controller creator method and should represent the folder name where the
+	// views for this controller can be found
+	ControllerName string
+
+	// RequestedPath is a quick reference member that contains the url path (after the domain) that
+	// was requested.
+	RequestedPath string
+
+	// QueryString is a quick reference member that contains the key value pair query string parameters
+	// that were submitted with this request
+	QueryString map[string]string
+
+	// Fragment represents the #Value portion of the requested URL (Note some sources indicate that URL
+	// fragments are or should be depreciated. Use URL fragments and this member at your discretion)
+	Fragment string
+
+	// DefaultAction is the name of the default action method name (in the route map) to try and execute
+	// when making a request directly to the controller.  (E.g. site.com/controller) (This should be your
+	// Index, Home or Default page name)
+	DefaultAction string
+
+	// ActionRoutes stores the routes which have been registered for this controller. This collection is
+	// used in the Execute method to find the appropriate action method function to call
+	ActionRoutes []*ActionMap
+
+	// TrustedProxies is the list of reverse proxy networks whose forwarding headers are honored
+	// by the ClientIP, Scheme and Host methods (set from the route manager)
+	TrustedProxies TrustedProxies
+
+	// ViewMinifier is used to minify the output of the View, SVGView and JSON results when
+	// MinifyOutput is set (set from the route manager)
+	ViewMinifier *ViewMinifier
+
+	// MinifyOutput enables minification of this controllers results, defaults to the MinifyViews
+	// configuration value. Can be changed in the BeforeExecute callback or action methods
+	MinifyOutput bool
+
+	// Layout is the name of the layout template that wraps this controllers views (see
+	// ViewCache.RenderView), defaults to the route managers DefaultLayout when not set by
+	// the controller. Can be changed in the BeforeExecute callback or action methods
+	Layout string
+
+	// ViewCache caches the resolved and parsed view templates of the View methods (set from
+	// the route manager, views are parsed on every render when nil)
+	ViewCache *ViewCache
+
+	// LiveReload is the development mode live reload endpoint, used by the LiveReload view
+	// function (set from the route manager, nil when disabled)
+	LiveReload *LiveReload
+
+	// ViewData is the preferred means of pasing data models to your views as of version 0.2.0.
+	ViewData map[string]interface{}
+
+	// TempData are the values stored by the previous request of this browser session with
+	// SetTempData, they are removed from the session once read (set from the route manager)
+	TempData map[string]interface{}
+
+	// User is the authenticated user making this request, set this from your authentication
+	// (E.g. in the BeforeExecute callback) to make it available to views as .User
+	User interface{}
+
+	// ModelState holds the validation errors of this request, available to views as .ModelState
+	ModelState ModelState
+
+	// ViewConfig is the subset of the application configuration available to views as .Config
+	// (set from the route manager)
+	ViewConfig *ViewConfig
+
+	// Culture is the culture of the request (E.g. "fr"), used to translate messages, format
+	// numbers and dates and select localized views (set from the route managers Localizer)
+	Culture string
+
+	// Localizer holds the message catalogs used by the T view function (set from the route
+	// manager, nil when localization is disabled)
+	Localizer *Localizer
+
+	// BeforeExecute is a callback method that a controller can set to provide a global method called before
+	// the action method is executed. (Controller global prep function)
+	BeforeExecute ControllerCallback
+
+	// AfterExecute is a callback method that a controller can set to provide a global method called after
+	// the action method is executed. (Controller global clean up function)
+	AfterExecute ControllerCallback
+
+	// ErrorResult is a callback method that a controller can set to respond to error conditions with a custom
+	// error page
+	ErrorResult ErrorResultCallback
+
+	// NotFoundResult is a callback method that a controller can set to respond to a content not found condition
+	// with a custom 404 page
+	NotFoundResult NotFoundResultCallback
+}
+
+// NewBaseController returns a reference to a new Base Controller
+func NewBaseController(request *http.Request) *Controller {
+	controllerName := strings.Split(request.URL.Path, "/")[0]
+	if controllerName == "" {
+		controllerName = "Home"
+	}
+
+	rtn := &Controller{
+		Request:          request,
+		Session:          NewSession(),
+		Cookies:          make([]*http.Cookie, 0),
+		ContinuePipeline: true,
+
+		ControllerName: controllerName,
+		RequestedPath:  request.URL.Path,
+		QueryString:    map[string]string{},
+		Fragment:       "",
+
+		DefaultAction: "",
+		ActionRoutes:  make([]*ActionMap, 0),
+		ViewData:      make(map[string]interface{}, 0),
+		TempData:      make(map[string]interface{}, 0),
+		ModelState:    NewModelState(),
+	}
+
+	for _, cookie := range request.Cookies() {
+		rtn.Cookies = append(rtn.Cookies, cookie)
+	}
+
+	return rtn
+}
+
+// Forwarded returns the client facing view of this request, honoring the forwarding headers of
+// trusted proxies (see TrustedProxies.Resolve)
+func (controller *Controller) Forwarded() *ForwardedRequest {
+	return controller.TrustedProxies.Resolve(controller.Request)
+}
+
+// ClientIP returns the IP address of the client that made this request
+func (controller *Controller) ClientIP() string {
+	return controller.Forwarded().ClientIP
+}
+
+// Scheme returns the url scheme (http or https) that the client used to make this request
+func (controller *Controller) Scheme() string {
+	return controller.Forwarded().Scheme
+}
+
+// Host returns the host (and optional port) that the client requested
+func (controller *Controller) Host() string {
+	return controller.Forwarded().Host
+}
+
+// RegisterAction allows package caller to map a controller action method to
+// a given Http Request verb and action name (E.g. site.com/Controller/ActionName)
+func (controller *Controller) RegisterAction(verb string, name string, method ActionMethod) {
+	controller.ActionRoutes = append(controller.ActionRoutes, NewActionMap(verb, name, method))
+}
+
+// GetCookie returns the requested cookie from this controllers collection
+func (controller *Controller) GetCookie(name string) *http.Cookie {
+	for _, v := range controller.Cookies {
+		if strings.EqualFold(v.Name, name) {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// SetCookie will overwrite or create a cookie in this controllers collection
+func (controller *Controller) SetCookie(cookie *http.Cookie) {
+	for k, v := range controller.Cookies {
+		if strings.EqualFold(v.Name, cookie.Name) {
+			controller.Cookies[k] = cookie
+			return
+		}
+	}
+
+	controller.Cookies = append(controller.Cookies, cookie)
+}
+
+// DeleteCookie will set the cookie (identified by provided ccokieName) to expire in
+// the past, thus making the browser remove it and stop sending it back.
+func (controller *Controller) DeleteCookie(cookieName string) {
+	for _, v := range controller.Cookies {
+		if strings.EqualFold(v.Name, cookieName) {
+			v.Expires = time.Now().Add(-1 * time.Hour)
+		}
+	}
+}
+
+// Execute is called by the route manager instructing this controller to respond
+func (controller *Controller) Execute() (*ActionResult, error) {
+	verb := controller.Request.Method
+	actionName := controller.DefaultAction
+	params := []string{}
+
+	if strings.Contains(strings.ToLower(controller.RequestedPath), "/") && controller.RequestedPath != "/" {
+		// Strips the leading / so we prevent the empty first parts element below
+		url := controller.RequestedPath
+		if strings.HasPrefix(url, "/") {
+			url = url[1:]
+		}
+
+		parts := strings.Split(url, "/")
+
+		if len(parts) > 1 {
+			actionName = parts[1]
+
+			if len(parts) > 2 {
+				params = parts[2:]
+			}
+		}
+	}
+
+	for _, actionMethod := range controller.ActionRoutes {
+		if strings.EqualFold(actionMethod.Name, actionName) && (len(actionMethod.Verb) <= 0 || strings.EqualFold(actionMethod.Verb, verb)) {
+			if strings.EqualFold(verb, "POST") {
+				if err := controller.Request.ParseForm(); err != nil {
+					return nil, err
+				}
+			}
+
+			res := actionMethod.Method(params)
+			return res, nil
+		}
+	}
+
+	if controller.NotFoundResult != nil {
+		return controller.NotFoundResult(), nil
+	}
+
+	return controller.DefaultNotFoundPage(), nil
+}
+
+// WriteResponse is called from the route manager to execute the result that was constructed
+// from this controllers Execute method (E.g. the result returned from the action if mapped)
+func (controller *Controller) WriteResponse(result *ActionResult) error {
+	if controller.ContinuePipeline {
+		if result == nil || (len(result.Data) <= 0 && result.Stream == nil) {
+			if controller.NotFoundResult != nil {
+				result = controller.NotFoundResult()
+			} else {
+				result = controller.DefaultNotFoundPage()
+			}
+		}
+
+		err := result.Execute(controller.Response)
+		return err
+	}
+
+	return nil
+}
+
+// RedirectJS is a helper method that will write a very simple html page using the
+// window.location.href='url' method to redirect the borwser to the provided url
+// Note this will also set the controller.ContinuePipeline to false, meaning that
+// the ActionMethod for this request will NOT be called. This allows us to use this
+// method from BeginExecute callbacks to lock down an entire controller to given
+// conditions, such as if the user is logged in. Can be called anytime before
+// AfterExecute.
+func (controller *Controller) RedirectJS(url string) error {
+	data := fmt.Sprintf("<html><head><title>Redirecting...</title><body><script type=\"text/javascript\">window.location.href='%s';</script></body></html>", url)
+
+	// We manually write the cookies to the browser here because we'll be breaking the
+	// standard pipelint (eg ContinuePipeline = false)
+	res := NewActionResult([]byte(data))
+	res.StatusCode = 200
+	res.Cookies = controller.Cookies
+	res.Headers["Cache-Control"] = "no-cache, no-store, must-revalidate"
+	res.Headers["Pragma"] = "no-cache"
+	res.Headers["Expires"] = "0"
+
+	if err := res.Execute(controller.Response); err != nil {
+		// Can't test this as it shouldn't be possible to fail here, more of a doomsday protection
+		return err
+	}
+
+	controller.ContinuePipeline = false
+	return nil
+}
+
+// Redirect returns a new ActionResult that redirects the browser to the provided url, root
+// relative urls keep the culture route prefix of this request (see LocalizeURL)
+func (controller *Controller) Redirect(url string) *ActionResult {
+	url = controller.LocalizeURL(url)
+
+	// The body is written for clients that don't follow redirects (as by http.Redirect)
+	res := NewActionResult([]byte(fmt.Sprintf("<a href=\"%s\">Found</a>.\n", template.HTMLEscapeString(url))))
+	res.StatusCode = http.StatusFound
+	res.Headers["Content-Type"] = "text/html; charset=utf-8"
+	res.Headers["Location"] = url
+	res.Cookies = controller.Cookies
+	return res
+}
+
+// RedirectToAction returns a new ActionResult that redirects the browser to the provided
+// controller, action and parameters (see URL)
+func (controller *Controller) RedirectToAction(controllerName string, actionName string, params ...interface{}) *ActionResult {
+	return controller.Redirect(controller.URL(append([]interface{}{controllerName, actionName}, params...)...))
+}
+
+// Result returns a new ActionResult and automatically assigns the controllers cookies
+func (controller *Controller) Result(data []byte) *ActionResult {
+	res := NewActionResult(data)
+	res.Cookies = controller.Cookies
+	return res
+}
+
+// View will take the provided array of template names and try to make an mvcapp Template
+// List (see func mvcapp.MakeTemplateList) using the type name of this controller (from
+// reflection). The view is rendered by the ViewEngine registered for the extension of the
+// first template (see ViewEngines), the resolved list and parsed templates are cached by
+// the ViewCache. The model is passed to the view as .Model of the ViewModel envelope, which
+// includes the ViewData, TempData, User and ModelState of this controller. Then returns the
+// ViewResult that is created
+func (controller *Controller) View(templates []string, model interface{}) *ActionResult {
+	res := controller.renderView(templates, model)
+	if mimeType, ok := res.Headers["Content-Type"]; ok && !strings.HasPrefix(mimeType, "text/html") {
+		return res
+	}
+
+	return controller.MinifyResult(res, "text/html")
+}
+
+// renderView renders the provided templates and model (see View) without minifying the result
+func (controller *Controller) renderView(templates []string, model interface{}) *ActionResult {
+	controllerName := strings.ToLower(controller.ControllerName)
+	templates = controller.ViewCache.LocalizeTemplates(controllerName, controller.Culture, templates)
+	layout := controller.Layout
+	if layout != "" {
+		layout = controller.ViewCache.LocalizeTemplates(controllerName, controller.Culture, []string{layout})[0]
+	}
+
+	context := &ViewContext{
+		ControllerName: controllerName,
+		Layout:         layout,
+		Templates:      templates,
+		Model:          controller.NewViewModel(model),
+		Funcs:          controller.ViewFuncs(),
+		Cache:          controller.ViewCache,
+	}
+
+	engine := ViewEngines.Default
+	if len(templates) > 0 {
+		engine = ViewEngines.Engine(templates[0])
+	}
+
+	res, err := engine.Render(context)
+	if err != nil {
+		if controller.ErrorResult != nil {
+			return controller.ErrorResult(errors.New("Internal server error, failed to render page"))
+		}
+
+		return controller.DefaultErrorPage(errors.New("Internal server error, failed to render page"))
+	}
+
+	res.Cookies = controller.Cookies
+	return res
+}
+
+// SVGView renders the provided templates (see View) as an svg image, minified once with the
+// svg minifier rather than the html minifier used by View
+func (controller *Controller) SVGView(templates []string, model interface{}) *ActionResult {
+	res := controller.renderView(templates, model)
+	if res.StatusCode == 200 {
+		res.Headers["Content-Type"] = "image/svg+xml"
+		res = controller.MinifyResult(res, "image/svg+xml")
+	}
+
+	return res
+}
+
+// MinifyResult minifies the data of the provided result as mimeType when this controllers
+// MinifyOutput is set, returns the result
+func (controller *Controller) MinifyResult(result *ActionResult, mimeType string) *ActionResult {
+	if result != nil && controller.MinifyOutput && controller.ViewMinifier != nil {
+		result.Data = controller.ViewMinifier.Minify(mimeType, result.Data)
+	}
+
+	return result
+}
+
+// ViewFuncs returns the controller specific template functions made available to views, such
+// as {{ Bundle "site.css" }} which renders the include tag of a content bundle
+func (controller *Controller) ViewFuncs() template.FuncMap {
+	rtn := template.FuncMap{
+		"Bundle":          controller.Bundle,
+		"BundleIntegrity": controller.BundleIntegrity,
+		"LiveReload":      controller.LiveReloadScript,
+		"component":       controller.Component,
+	}
+
+	for name, fn := range controller.localizedViewFuncs() {
+		rtn[name] = fn
+	}
+
+	return rtn
+}
+
+// LiveReloadScript returns the script tag that refreshes the page when the watched files change,
+// returns an empty string when live reload is disabled (E.g. outside of DevelopmentMode)
+func (controller *Controller) LiveReloadScript() template.HTML {
+	if controller.LiveReload == nil {
+		return ""
+	}
+
+	return controller.LiveReload.Script()
+}
+
+// BundleIntegrity returns the subresource integrity hash of the named content bundle
+func (controller *Controller) BundleIntegrity(bundleName string) (string, error) {
+	if controller.BundleManager == nil {
+		return "", errors.New("Can not get bundle integrity, no bundle manager registered")
+	}
+
+	return controller.BundleManager.BundleIntegrity(bundleName)
+}
+
+// Bundle returns the html tag that includes the named content bundle (see BundleManager.BundleTag)
+func (controller *Controller) Bundle(bundleName string) (template.HTML, error) {
+	if controller.BundleManager == nil {
+		return "", errors.New("Can not include bundle, no bundle manager registered")
+	}
+
+	return controller.BundleManager.BundleTag(bundleName)
+}
+
+// SimpleView takes the provided variadic strings and uses them to call controller.View(templates, nil)
+// You can pass custom data models by setting them to the controller.ViewData map, which can be accessed in the
+// template via .ViewData["key"] (see ViewModel)
+func (controller *Controller) SimpleView(templates ...string) *ActionResult {
+	return controller.View(templates, nil)
+}
+
+// CSRFToken returns the cross site request forgery token of the users browser session, creating
+// it when needed. Views include it in forms with {{ .CSRFField }}
+func (controller *Controller) CSRFToken() string {
+	if controller.Session == nil {
+		return ""
+	}
+
+	token, ok := controller.Session.Get(csrfTokenSessionKey).(string)
+	if !ok || token == "" {
+		var err error
+		if token, err = newCSRFToken(); err != nil {
+			LogErrorf("Failed to generate CSRF token: %s", err)
+			return ""
+		}
+
+		controller.Session.Set(csrfTokenSessionKey, token)
+	}
+
+	return token
+}
+
+// newCSRFToken returns 32 bytes read from crypto/rand encoded as an url safe string
+func newCSRFToken() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// ValidateCSRFToken returns true if the request includes the users CSRFToken in the form field
+// named CSRFFieldName or the X-CSRF-Token header
+func (controller *Controller) ValidateCSRFToken() bool {
+	if controller.Request == nil || controller.Session == nil {
+		return false
+	}
+
+	expected, ok := controller.Session.Get(csrfTokenSessionKey).(string)
+	if !ok || expected == "" {
+		return false
+	}
+
+	token := controller.Request.Header.Get("X-CSRF-Token")
+	if token == "" {
+		token = controller.Request.FormValue(CSRFFieldName)
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+// SetTempData stores a value that is available to the next request of this browser session as
+// TempData (E.g. a status message shown after a redirect)
+func (controller *Controller) SetTempData(key string, value interface{}) {
+	if controller.Session == nil {
+		return
+	}
+
+	values, ok := controller.Session.Get(tempDataSessionKey).(map[string]interface{})
+	if !ok {
+		values = make(map[string]interface{}, 0)
+		controller.Session.Set(tempDataSessionKey, values)
+	}
+
+	values[key] = value
+}
+
+// loadTempData moves the values stored by the previous request from the session to TempData
+func (controller *Controller) loadTempData() {
+	if values, ok := controller.Session.Get(tempDataSessionKey).(map[string]interface{}); ok {
+		controller.TempData = values
+		controller.Session.Remove(tempDataSessionKey)
+	}
+}
+
+// JSON returns a new JSONResult object of the provided payload
+func (controller *Controller) JSON(payload interface{}) *ActionResult {
+	res, err := NewJSONResult(payload)
+	if err != nil {
+		res = &ActionResult{
+			Data: []byte(fmt.Sprintf("{\"Success\":false,\"Error\":\"%s\"}", err)),
+		}
+	}
+
+	res.Cookies = controller.Cookies
+	return controller.MinifyResult(res, "application/json")
+}
+
+// ToController is a method defined by the controller object (which implements IController) that
+// returns a reference to the Controller object it is called on. We use this in the route manager
+// to gain access to the session and cookie collections of the base controller from a custom controller
+func (controller *Controller) ToController() *Controller {
+	return controller
+}
+
+// DefaultErrorPage will attempt to render the built in error page
+func (controller *Controller) DefaultErrorPage(err error) *ActionResult {
+	LogWarning(fmt.Sprintf("Servind default error page because: %s", err.Error()))
+	html := fmt.Sprintf("<html><head><title>Server Error</title></head><body><h1>Server Error :(</h1>%s</body></html>", err.Error())
+	data := []byte(html)
+	res := NewActionResult(data)
+	res.Cookies = controller.Cookies
+	res.StatusCode = 500
+
+	return res
+}
+
+// DefaultNotFoundPage will attempt to render the built in 404 page
+func (controller *Controller) DefaultNotFoundPage() *ActionResult {
+	LogWarning(fmt.Sprintf("Serving default not found page because: %s", controller.RequestedPath))
+	html := fmt.Sprintf("<html><head><title>Content Not Found</title></head><body><h1>Content Missing</h1>We're sorry, we could not find '%s' from this app :(</body></html>", controller.RequestedPath)
+	data := []byte(html)
+	res := NewActionResult(data)
+	res.Cookies = controller.Cookies
+	res.StatusCode = 404
+
+	return res
+}
@@ -0,0 +1,328 @@
+/*
+	Digivance MVC Application Framework
+	Culture Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the culture specific rules used by the localization features: the plural
+	rules that select the plural form of a message for a count, and the number, currency and date
+	formats of each culture. Cultures are language tags (E.g. "fr" or "fr-CA"), a culture without
+	its own rules uses the rules of its language, falling back to english.
+*/
+
+package mvcapp
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Plural categories (see PluralRule), named after the Unicode CLDR plural categories
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule selects the plural category of a count for a language
+type PluralRule struct {
+	// Categories are the plural categories of the language, in the order of the msgstr[n]
+	// plural forms of PO files
+	Categories []string
+
+	// Select returns the plural category of the provided count
+	Select func(count float64) string
+}
+
+// CultureFormat defines how numbers, currencies and dates are formatted for a culture
+type CultureFormat struct {
+	// ThousandsSeparator separates the groups of thousands of numbers
+	ThousandsSeparator string
+
+	// DecimalSeparator separates the whole and fractional parts of numbers
+	DecimalSeparator string
+
+	// CurrencyPattern places the currency symbol, {symbol} and {amount} are replaced by the
+	// symbol and formatted amount (E.g. "{amount} {symbol}")
+	CurrencyPattern string
+
+	// DateLayouts are the Go time layouts of the "date", "time" and "datetime" layout names
+	DateLayouts map[string]string
+}
+
+// cultureMutex protects the plural rules and culture formats
+var cultureMutex sync.RWMutex
+
+// oneOther is the plural rule of languages where only a count of one is singular (E.g. english)
+var oneOther = &PluralRule{
+	Categories: []string{PluralOne, PluralOther},
+	Select: func(count float64) string {
+		if count == 1 {
+			return PluralOne
+		}
+
+		return PluralOther
+	},
+}
+
+// pluralRules are the registered plural rules by language
+var pluralRules = map[string]*PluralRule{
+	"en": oneOther,
+	"de": oneOther,
+	"nl": oneOther,
+	"it": oneOther,
+	"es": oneOther,
+	"sv": oneOther,
+	"da": oneOther,
+	"fr": {
+		Categories: []string{PluralOne, PluralOther},
+		Select: func(count float64) string {
+			if count >= 0 && count < 2 {
+				return PluralOne
+			}
+
+			return PluralOther
+		},
+	},
+	"pt": {
+		Categories: []string{PluralOne, PluralOther},
+		Select: func(count float64) string {
+			if count >= 0 && count < 2 {
+				return PluralOne
+			}
+
+			return PluralOther
+		},
+	},
+	"ru": {
+		Categories: []string{PluralOne, PluralFew, PluralMany},
+		Select:     slavicPlural(false),
+	},
+	"uk": {
+		Categories: []string{PluralOne, PluralFew, PluralMany},
+		Select:     slavicPlural(false),
+	},
+	"pl": {
+		Categories: []string{PluralOne, PluralFew, PluralMany},
+		Select:     slavicPlural(true),
+	},
+	"ja": {
+		Categories: []string{PluralOther},
+		Select:     func(count float64) string { return PluralOther },
+	},
+	"zh": {
+		Categories: []string{PluralOther},
+		Select:     func(count float64) string { return PluralOther },
+	},
+	"ko": {
+		Categories: []string{PluralOther},
+		Select:     func(count float64) string { return PluralOther },
+	},
+}
+
+// slavicPlural returns the one, few and many plural rule of russian and polish, where polish
+// only uses one for a count of exactly one
+func slavicPlural(exactOne bool) func(count float64) string {
+	return func(count float64) string {
+		if count != math.Trunc(count) {
+			return PluralOther
+		}
+
+		n := int64(math.Abs(count))
+		switch {
+		case n == 1 || (!exactOne && n%10 == 1 && n%100 != 11):
+			return PluralOne
+		case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+			return PluralFew
+		}
+
+		return PluralMany
+	}
+}
+
+// cultureFormats are the registered culture formats by culture
+var cultureFormats = map[string]*CultureFormat{
+	"en": {
+		ThousandsSeparator: ",",
+		DecimalSeparator:   ".",
+		CurrencyPattern:    "{symbol}{amount}",
+		DateLayouts:        map[string]string{"date": "Jan 2, 2006", "time": "3:04 PM", "datetime": "Jan 2, 2006 3:04 PM"},
+	},
+	"en-GB": {
+		ThousandsSeparator: ",",
+		DecimalSeparator:   ".",
+		CurrencyPattern:    "{symbol}{amount}",
+		DateLayouts:        map[string]string{"date": "2 Jan 2006", "time": "15:04", "datetime": "2 Jan 2006 15:04"},
+	},
+	"fr": {
+		ThousandsSeparator: " ",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{amount} {symbol}",
+		DateLayouts:        map[string]string{"date": "02/01/2006", "time": "15:04", "datetime": "02/01/2006 15:04"},
+	},
+	"de": {
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{amount} {symbol}",
+		DateLayouts:        map[string]string{"date": "02.01.2006", "time": "15:04", "datetime": "02.01.2006 15:04"},
+	},
+	"es": {
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{amount} {symbol}",
+		DateLayouts:        map[string]string{"date": "02/01/2006", "time": "15:04", "datetime": "02/01/2006 15:04"},
+	},
+	"it": {
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{amount} {symbol}",
+		DateLayouts:        map[string]string{"date": "02/01/2006", "time": "15:04", "datetime": "02/01/2006 15:04"},
+	},
+	"nl": {
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{symbol} {amount}",
+		DateLayouts:        map[string]string{"date": "02-01-2006", "time": "15:04", "datetime": "02-01-2006 15:04"},
+	},
+	"pt": {
+		ThousandsSeparator: ".",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{symbol} {amount}",
+		DateLayouts:        map[string]string{"date": "02/01/2006", "time": "15:04", "datetime": "02/01/2006 15:04"},
+	},
+	"ru": {
+		ThousandsSeparator: " ",
+		DecimalSeparator:   ",",
+		CurrencyPattern:    "{amount} {symbol}",
+		DateLayouts:        map[string]string{"date": "02.01.2006", "time": "15:04", "datetime": "02.01.2006 15:04"},
+	},
+	"ja": {
+		ThousandsSeparator: ",",
+		DecimalSeparator:   ".",
+		CurrencyPattern:    "{symbol}{amount}",
+		DateLayouts:        map[string]string{"date": "2006/01/02", "time": "15:04", "datetime": "2006/01/02 15:04"},
+	},
+}
+
+// NormalizeCulture returns the provided culture in its canonical form, a lower case language
+// and upper case region (E.g. "fr_ca" is "fr-CA")
+func NormalizeCulture(culture string) string {
+	parts := strings.Split(strings.Replace(strings.TrimSpace(culture), "_", "-", -1), "-")
+	if parts[0] == "" {
+		return ""
+	}
+
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		} else if len(parts[i]) == 4 {
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
+
+// CultureLanguage returns the language of the provided culture (E.g. "fr-CA" is "fr")
+func CultureLanguage(culture string) string {
+	culture = NormalizeCulture(culture)
+	if i := strings.Index(culture, "-"); i >= 0 {
+		return culture[:i]
+	}
+
+	return culture
+}
+
+// RegisterPluralRule adds (or replaces) the plural rule of the provided language
+func RegisterPluralRule(language string, rule *PluralRule) {
+	cultureMutex.Lock()
+	defer cultureMutex.Unlock()
+
+	pluralRules[CultureLanguage(language)] = rule
+}
+
+// GetPluralRule returns the plural rule of the provided culture, the english rule is returned
+// for languages without a registered rule
+func GetPluralRule(culture string) *PluralRule {
+	cultureMutex.RLock()
+	defer cultureMutex.RUnlock()
+
+	if rule := pluralRules[CultureLanguage(culture)]; rule != nil {
+		return rule
+	}
+
+	return oneOther
+}
+
+// PluralCategory returns the plural category of the provided count in the provided culture
+func PluralCategory(culture string, count float64) string {
+	return GetPluralRule(culture).Select(count)
+}
+
+// RegisterCultureFormat adds (or replaces) the format of the provided culture
+func RegisterCultureFormat(culture string, format *CultureFormat) {
+	cultureMutex.Lock()
+	defer cultureMutex.Unlock()
+
+	cultureFormats[NormalizeCulture(culture)] = format
+}
+
+// GetCultureFormat returns the format of the provided culture, or of its language, falling
+// back to the english format
+func GetCultureFormat(culture string) *CultureFormat {
+	cultureMutex.RLock()
+	defer cultureMutex.RUnlock()
+
+	if format := cultureFormats[NormalizeCulture(culture)]; format != nil {
+		return format
+	}
+
+	if format := cultureFormats[CultureLanguage(culture)]; format != nil {
+		return format
+	}
+
+	return cultureFormats["en"]
+}
+
+// FormatNumber formats the provided number with the provided number of decimals and the
+// separators of this culture
+func (format *CultureFormat) FormatNumber(value interface{}, decimals int) (string, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	return formatNumber(number, decimals, format.ThousandsSeparator, format.DecimalSeparator), nil
+}
+
+// FormatCurrency formats the provided amount with two decimals and the provided currency
+// symbol following the CurrencyPattern of this culture
+func (format *CultureFormat) FormatCurrency(value interface{}, symbol string) (string, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	amount := formatNumber(math.Abs(number), 2, format.ThousandsSeparator, format.DecimalSeparator)
+	rtn := strings.Replace(strings.Replace(format.CurrencyPattern, "{amount}", amount, -1), "{symbol}", symbol, -1)
+	if number < 0 {
+		rtn = "-" + rtn
+	}
+
+	return rtn, nil
+}
+
+// FormatDate formats the provided time using the provided layout, which can be a Go time layout,
+// one of the "date", "time" and "datetime" names of this culture, or "iso" or "rfc1123"
+func (format *CultureFormat) FormatDate(value time.Time, layout string) string {
+	if cultureLayout, ok := format.DateLayouts[strings.ToLower(layout)]; ok {
+		return value.Format(cultureLayout)
+	}
+
+	return FormatDate(value, layout)
+}
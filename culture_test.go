@@ -0,0 +1,106 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Culture Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of culture.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in culture.go
+*/
+
+package mvcapp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestNormalizeCulture ensures that cultures are returned in their canonical form
+func TestNormalizeCulture(t *testing.T) {
+	cases := map[string]string{
+		"fr":         "fr",
+		"FR_ca":      "fr-CA",
+		" en-us ":    "en-US",
+		"zh-hant-tw": "zh-Hant-TW",
+		"":           "",
+	}
+
+	for culture, expected := range cases {
+		if result := mvcapp.NormalizeCulture(culture); result != expected {
+			t.Errorf("Unexpected culture %s for %s", result, culture)
+		}
+	}
+
+	if mvcapp.CultureLanguage("pt_BR") != "pt" {
+		t.Error("Unexpected culture language")
+	}
+}
+
+// TestPluralCategory ensures that the plural rules select the expected categories
+func TestPluralCategory(t *testing.T) {
+	cases := []struct {
+		culture  string
+		count    float64
+		expected string
+	}{
+		{"en", 1, mvcapp.PluralOne},
+		{"en-US", 0, mvcapp.PluralOther},
+		{"fr", 0, mvcapp.PluralOne},
+		{"fr-CA", 1.5, mvcapp.PluralOne},
+		{"fr", 2, mvcapp.PluralOther},
+		{"ru", 21, mvcapp.PluralOne},
+		{"ru", 3, mvcapp.PluralFew},
+		{"ru", 12, mvcapp.PluralMany},
+		{"pl", 21, mvcapp.PluralMany},
+		{"pl", 22, mvcapp.PluralFew},
+		{"ja", 1, mvcapp.PluralOther},
+		{"xx", 1, mvcapp.PluralOne},
+	}
+
+	for _, c := range cases {
+		if result := mvcapp.PluralCategory(c.culture, c.count); result != c.expected {
+			t.Errorf("Unexpected plural category %s for %v in %s", result, c.count, c.culture)
+		}
+	}
+}
+
+// TestCultureFormat ensures that numbers, currencies and dates follow the culture
+func TestCultureFormat(t *testing.T) {
+	date := time.Date(2024, 3, 9, 14, 5, 0, 0, time.UTC)
+
+	en := mvcapp.GetCultureFormat("en-US")
+	if value, _ := en.FormatNumber(1234.5, 2); value != "1,234.50" {
+		t.Errorf("Unexpected english number: %s", value)
+	}
+
+	if value, _ := en.FormatCurrency(-1234.5, "$"); value != "-$1,234.50" {
+		t.Errorf("Unexpected english currency: %s", value)
+	}
+
+	if value := en.FormatDate(date, "date"); value != "Mar 9, 2024" {
+		t.Errorf("Unexpected english date: %s", value)
+	}
+
+	fr := mvcapp.GetCultureFormat("fr-CA")
+	if value, _ := fr.FormatNumber(1234567.891, 2); value != "1 234 567,89" {
+		t.Errorf("Unexpected french number: %s", value)
+	}
+
+	if value, _ := fr.FormatCurrency(1234.5, "€"); value != "1 234,50 €" {
+		t.Errorf("Unexpected french currency: %s", value)
+	}
+
+	if value := mvcapp.GetCultureFormat("de").FormatDate(date, "datetime"); value != "09.03.2024 14:05" {
+		t.Errorf("Unexpected german date: %s", value)
+	}
+
+	if value := fr.FormatDate(date, "iso"); value != "2024-03-09T14:05:00Z" {
+		t.Errorf("Unexpected iso date: %s", value)
+	}
+
+	mvcapp.RegisterCultureFormat("xx", &mvcapp.CultureFormat{ThousandsSeparator: "'", DecimalSeparator: ".", CurrencyPattern: "{symbol} {amount}"})
+	if value, _ := mvcapp.GetCultureFormat("xx-YY").FormatCurrency(1000, "CHF"); value != "CHF 1'000.00" {
+		t.Errorf("Unexpected registered culture format: %s", value)
+	}
+}
@@ -8,3 +8,6 @@ go get gopkg.in/gomail.v2
 
 # Minify from tdewolff for the bundle controller
 go get github.com/tdewolff/minify
+
+# ACME protocol client for automatic TLS certificates
+go get golang.org/x/crypto/acme
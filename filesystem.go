@@ -0,0 +1,277 @@
+/*
+	Digivance MVC Application Framework
+	File System Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the application file system. The views, static files, content bundle sources,
+	bundle manifest and configuration files are read through FileSystem (any fs.FS, such as an
+	embed.FS) so that a whole site can be shipped inside of a single binary. When FileSystem is
+	nil the files are read from the disk next to the executable (see GetApplicationPath). An
+	overlay can be used during development so that files on the disk override the embedded ones.
+	Files that are written (built bundles, certificates and logs) always use the disk.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+)
+
+// FileSystem is the file system the application files are read from, its paths are relative to
+// the application path (E.g. "views/home/index.htm"). Nil reads the files from the disk
+var FileSystem fs.FS
+
+// UseFileSystem sets the FileSystem the application files are read from. When overlay is true
+// files that exist on the disk (next to the executable) take precedence over the provided file
+// system, so views and static files can be edited without rebuilding during development.
+// Use fs.Sub to remove the folder prefix of an embed.FS (E.g. fs.Sub(siteFS, "site"))
+func UseFileSystem(fsys fs.FS, overlay bool) {
+	if overlay && fsys != nil {
+		fsys = NewOverlayFS(os.DirFS(GetApplicationPath()), fsys)
+	}
+
+	FileSystem = fsys
+}
+
+// OverlayFS is a read only fs.FS made of layers, each file is read from the first layer that
+// contains it and directory listings are merged
+type OverlayFS struct {
+	// Layers are the file systems in order of precedence
+	Layers []fs.FS
+}
+
+// NewOverlayFS returns a new OverlayFS of the provided layers, in order of precedence
+func NewOverlayFS(layers ...fs.FS) *OverlayFS {
+	return &OverlayFS{
+		Layers: layers,
+	}
+}
+
+// Open opens the named file from the first layer that contains it
+func (overlay *OverlayFS) Open(name string) (fs.File, error) {
+	for _, layer := range overlay.Layers {
+		file, err := layer.Open(name)
+		if err == nil {
+			return file, nil
+		}
+
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns the merged entries of the named directory in each of the layers, sorted by name
+func (overlay *OverlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries := make(map[string]fs.DirEntry, 0)
+	found := false
+	for _, layer := range overlay.Layers {
+		layerEntries, err := fs.ReadDir(layer, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		found = true
+		for _, entry := range layerEntries {
+			if _, ok := entries[entry.Name()]; !ok {
+				entries[entry.Name()] = entry
+			}
+		}
+	}
+
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+
+	rtn := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		rtn = append(rtn, entry)
+	}
+
+	sort.Slice(rtn, func(i int, j int) bool { return rtn[i].Name() < rtn[j].Name() })
+	return rtn, nil
+}
+
+// fileSystemPath returns the FileSystem path of the provided full path and filename, returns
+// false if there is no FileSystem or the file is outside of the application path
+func fileSystemPath(filename string) (string, bool) {
+	if FileSystem == nil {
+		return "", false
+	}
+
+	root := filepath.ToSlash(GetApplicationPath())
+	filename = filepath.ToSlash(filename)
+	if filename == root {
+		return ".", true
+	}
+
+	if !strings.HasPrefix(filename, root+"/") {
+		return "", false
+	}
+
+	name := path.Clean(strings.TrimPrefix(filename, root+"/"))
+	return name, fs.ValidPath(name)
+}
+
+// statFile returns the file info of the provided full path and filename
+func statFile(filename string) (os.FileInfo, error) {
+	if name, ok := fileSystemPath(filename); ok {
+		return fs.Stat(FileSystem, name)
+	}
+
+	return os.Stat(filename)
+}
+
+// readFile returns the content of the provided full path and filename
+func readFile(filename string) ([]byte, error) {
+	if name, ok := fileSystemPath(filename); ok {
+		return fs.ReadFile(FileSystem, name)
+	}
+
+	return ioutil.ReadFile(filename)
+}
+
+// walkFiles walks the provided folder (see filepath.Walk), the walked names are full paths
+func walkFiles(root string, walkFn filepath.WalkFunc) error {
+	name, ok := fileSystemPath(root)
+	if !ok {
+		return filepath.Walk(root, walkFn)
+	}
+
+	return fs.WalkDir(FileSystem, name, func(current string, entry fs.DirEntry, err error) error {
+		filename := root
+		if current != name {
+			filename = filepath.Join(root, filepath.FromSlash(strings.TrimPrefix(current, name+"/")))
+		}
+
+		if err != nil {
+			return walkFn(filename, nil, err)
+		}
+
+		info, err := entry.Info()
+		return walkFn(filename, info, err)
+	})
+}
+
+// openSeekableFile opens the provided full path and filename for http.ServeContent
+func openSeekableFile(filename string) (io.ReadSeeker, func() error, error) {
+	if name, ok := fileSystemPath(filename); ok {
+		file, err := FileSystem.Open(name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if seeker, ok := file.(io.ReadSeeker); ok {
+			return seeker, file.Close, nil
+		}
+
+		defer file.Close()
+		data, err := ioutil.ReadAll(file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return bytes.NewReader(data), func() error { return nil }, nil
+	}
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, file.Close, nil
+}
+
+// serveFile serves the provided full path and filename (see http.ServeFile)
+func serveFile(response http.ResponseWriter, request *http.Request, filename string) {
+	if _, ok := fileSystemPath(filename); !ok {
+		http.ServeFile(response, request, filename)
+		return
+	}
+
+	si, err := statFile(filename)
+	if err != nil || si.IsDir() {
+		http.NotFound(response, request)
+		return
+	}
+
+	content, closeFile, err := openSeekableFile(filename)
+	if err != nil {
+		http.NotFound(response, request)
+		return
+	}
+	defer closeFile()
+
+	http.ServeContent(response, request, filename, si.ModTime(), content)
+}
+
+// parseTemplateFiles parses the provided full path and filenames into the provided html
+// template set, naming each template after its file (see template.ParseFiles)
+func parseTemplateFiles(page *template.Template, files ...string) (*template.Template, error) {
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("html/template: no files named in call to ParseFiles")
+	}
+
+	for _, filename := range files {
+		data, err := readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl := page
+		if name := filepath.Base(filename); name != page.Name() {
+			tmpl = page.New(name)
+		}
+
+		if _, err := tmpl.Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
+
+// parseTextTemplateFiles parses the provided full path and filenames into the provided text
+// template set, naming each template after its file (see template.ParseFiles)
+func parseTextTemplateFiles(page *texttemplate.Template, files ...string) (*texttemplate.Template, error) {
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("template: no files named in call to ParseFiles")
+	}
+
+	for _, filename := range files {
+		data, err := readFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		tmpl := page
+		if name := filepath.Base(filename); name != page.Name() {
+			tmpl = page.New(name)
+		}
+
+		if _, err := tmpl.Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return page, nil
+}
@@ -0,0 +1,116 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	File System Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of filesystem.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in filesystem.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestUseFileSystem ensures that views, static files and configuration are read from the FileSystem
+func TestUseFileSystem(t *testing.T) {
+	embedded := fstest.MapFS{
+		"views/fstest/index.htm": &fstest.MapFile{Data: []byte("{{ define \"mvcapp\" }}Embedded {{ . }}{{ end }}")},
+		"static/site.txt":        &fstest.MapFile{Data: []byte("static content")},
+		"fstest.json":            &fstest.MapFile{Data: []byte("{\"AppName\":\"Embedded\"}")},
+	}
+
+	mvcapp.UseFileSystem(embedded, false)
+	defer mvcapp.UseFileSystem(nil, false)
+
+	templates := mvcapp.MakeTemplateList("fstest", []string{"index.htm"})
+	if len(templates) != 1 || !mvcapp.TemplateExists("fstest", "index.htm") {
+		t.Fatalf("Failed to find the embedded view: %v", templates)
+	}
+
+	res, err := mvcapp.NewViewResult(templates, "World")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(res.Data) != "Embedded World" {
+		t.Errorf("Unexpected view result: %s", res.Data)
+	}
+
+	config, err := mvcapp.NewConfigurationManagerFromFile("./fstest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.AppName != "Embedded" {
+		t.Errorf("Unexpected configuration: %s", config.AppName)
+	}
+
+	manager := mvcapp.NewRouteManager()
+	req := httptest.NewRequest("GET", "http://localhost/static/site.txt", nil)
+	rec := httptest.NewRecorder()
+	if !manager.ServeFile(rec, req) || rec.Code != http.StatusOK || rec.Body.String() != "static content" {
+		t.Errorf("Failed to serve the embedded static file: %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+// TestOverlayFS ensures that files on the disk take precedence over the embedded files
+func TestOverlayFS(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/overlay", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/overlay/index.htm", views), []byte("disk"), 0644)
+
+	embedded := fstest.MapFS{
+		"views/overlay/index.htm": &fstest.MapFile{Data: []byte("embedded")},
+		"views/overlay/about.htm": &fstest.MapFile{Data: []byte("about")},
+	}
+
+	mvcapp.UseFileSystem(embedded, true)
+	defer mvcapp.UseFileSystem(nil, false)
+
+	overlay := mvcapp.FileSystem
+	data, err := fs.ReadFile(overlay, "views/overlay/index.htm")
+	if err != nil || string(data) != "disk" {
+		t.Errorf("Expected the disk file to take precedence: %s (%v)", data, err)
+	}
+
+	data, err = fs.ReadFile(overlay, "views/overlay/about.htm")
+	if err != nil || string(data) != "about" {
+		t.Errorf("Expected the embedded file: %s (%v)", data, err)
+	}
+
+	if len(mvcapp.MakeTemplateList("overlay", []string{"index.htm", "about.htm"})) != 2 {
+		t.Error("Failed to resolve the overlay views")
+	}
+}
+
+// TestApplication_UseFileSystem ensures that the embedded views are precompiled when the application
+// is run and that a broken view stops the application
+func TestApplication_UseFileSystem(t *testing.T) {
+	app := mvcapp.NewApplicationFromConfig(mvcapp.NewConfigurationManager())
+	app.UseFileSystem(fstest.MapFS{
+		"views/broken/index.htm": &fstest.MapFile{Data: []byte("{{ define \"mvcapp\" }}{{ if }}{{ end }}")},
+	})
+	defer mvcapp.UseFileSystem(nil, false)
+
+	if err := app.Run(); err == nil || !strings.Contains(err.Error(), "Failed to precompile views") {
+		t.Fatalf("Failed to stop the application on a broken embedded view: %v", err)
+	}
+
+	if app.HTTPServer != nil {
+		t.Error("Failed to stop before serving")
+	}
+}
@@ -0,0 +1,251 @@
+/*
+	Digivance MVC Application Framework
+	Reverse Proxy Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the trusted proxy list and the parsing of the RFC 7239 Forwarded and the
+	X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host headers. This allows the framework to
+	see the client address, scheme and host when deployed behind reverse proxies or load balancers.
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ForwardedRequest is the client facing view of a request, E.g. the address, scheme and host
+// that the client used before the request passed through any trusted proxies
+type ForwardedRequest struct {
+	// ClientIP is the IP address of the client that made the request
+	ClientIP string
+
+	// Scheme is the url scheme (http or https) that the client used
+	Scheme string
+
+	// Host is the host (and optional port) that the client requested
+	Host string
+}
+
+// TrustedProxies is the list of networks that reverse proxies forward requests from. Forwarding
+// headers are only honored when the request arrives from one of these networks
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies returns the TrustedProxies list from the provided CIDR strings, plain
+// IP addresses are treated as single host networks
+func ParseTrustedProxies(cidrs []string) (TrustedProxies, error) {
+	rtn := TrustedProxies{}
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		if !strings.Contains(cidr, "/") {
+			ip := net.ParseIP(cidr)
+			if ip == nil {
+				return nil, fmt.Errorf("Failed to parse trusted proxy address: %s", cidr)
+			}
+
+			bits := 32
+			if ip.To4() == nil {
+				bits = 128
+			}
+
+			cidr = fmt.Sprintf("%s/%d", cidr, bits)
+		}
+
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse trusted proxy network: %s", err)
+		}
+
+		rtn = append(rtn, network)
+	}
+
+	return rtn, nil
+}
+
+// Contains returns true if the provided ip address belongs to one of the trusted proxy networks
+func (proxies TrustedProxies) Contains(ip string) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+
+	for _, network := range proxies {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwardedElement is a single hop of the Forwarded (or X-Forwarded-*) header chain
+type forwardedElement struct {
+	For   string
+	Proto string
+	Host  string
+}
+
+// parseForwardedNode strips the quotes, brackets and port of an RFC 7239 node value
+func parseForwardedNode(node string) string {
+	node = strings.Trim(node, "\"")
+	if strings.HasPrefix(node, "[") {
+		if end := strings.Index(node, "]"); end > 0 {
+			return node[1:end]
+		}
+	}
+
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+
+	return node
+}
+
+// parseForwarded parses the RFC 7239 Forwarded header values into the chain of hops
+func parseForwarded(values []string) []*forwardedElement {
+	rtn := []*forwardedElement{}
+
+	for _, value := range values {
+		for _, element := range strings.Split(value, ",") {
+			hop := &forwardedElement{}
+
+			for _, pair := range strings.Split(element, ";") {
+				kvp := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+				if len(kvp) != 2 {
+					continue
+				}
+
+				switch strings.ToLower(kvp[0]) {
+				case "for":
+					hop.For = parseForwardedNode(kvp[1])
+				case "proto":
+					hop.Proto = strings.ToLower(strings.Trim(kvp[1], "\""))
+				case "host":
+					hop.Host = strings.Trim(kvp[1], "\"")
+				}
+			}
+
+			rtn = append(rtn, hop)
+		}
+	}
+
+	return rtn
+}
+
+// splitHeaderList returns the trimmed comma separated values of the provided header
+func splitHeaderList(header http.Header, key string) []string {
+	rtn := []string{}
+
+	for _, value := range header.Values(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				rtn = append(rtn, item)
+			}
+		}
+	}
+
+	return rtn
+}
+
+// parseXForwarded parses the X-Forwarded-For, X-Forwarded-Proto and X-Forwarded-Host headers into
+// the chain of hops. Proto and host are normally set (not appended) by the proxy, when they hold a
+// list matching the X-Forwarded-For entries the matching entry is used for each hop
+func parseXForwarded(header http.Header) []*forwardedElement {
+	rtn := []*forwardedElement{}
+	forwardedFor := splitHeaderList(header, "X-Forwarded-For")
+	protos := splitHeaderList(header, "X-Forwarded-Proto")
+	hosts := splitHeaderList(header, "X-Forwarded-Host")
+
+	for i, addr := range forwardedFor {
+		hop := &forwardedElement{For: parseForwardedNode(addr)}
+
+		if len(protos) == len(forwardedFor) {
+			hop.Proto = strings.ToLower(protos[i])
+		} else if len(protos) > 0 {
+			hop.Proto = strings.ToLower(protos[0])
+		}
+
+		if len(hosts) == len(forwardedFor) {
+			hop.Host = hosts[i]
+		} else if len(hosts) > 0 {
+			hop.Host = hosts[0]
+		}
+
+		rtn = append(rtn, hop)
+	}
+
+	// Some proxies only set the proto / host headers
+	if len(rtn) <= 0 && (len(protos) > 0 || len(hosts) > 0) {
+		hop := &forwardedElement{}
+		if len(protos) > 0 {
+			hop.Proto = strings.ToLower(protos[0])
+		}
+
+		if len(hosts) > 0 {
+			hop.Host = hosts[0]
+		}
+
+		rtn = append(rtn, hop)
+	}
+
+	return rtn
+}
+
+// Resolve returns the client facing view of the provided request. If the request arrived from a
+// trusted proxy, the Forwarded header (or the X-Forwarded-* headers if absent) are walked from the
+// nearest hop outwards, skipping trusted proxies, to find the client address, scheme and host
+func (proxies TrustedProxies) Resolve(request *http.Request) *ForwardedRequest {
+	rtn := &ForwardedRequest{
+		ClientIP: request.RemoteAddr,
+		Scheme:   "http",
+		Host:     request.Host,
+	}
+
+	if host, _, err := net.SplitHostPort(request.RemoteAddr); err == nil {
+		rtn.ClientIP = host
+	}
+
+	if request.TLS != nil {
+		rtn.Scheme = "https"
+	}
+
+	if len(proxies) <= 0 || !proxies.Contains(rtn.ClientIP) {
+		return rtn
+	}
+
+	hops := parseForwarded(request.Header.Values("Forwarded"))
+	if len(hops) <= 0 {
+		hops = parseXForwarded(request.Header)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := hops[i]
+
+		if hop.Proto == "http" || hop.Proto == "https" {
+			rtn.Scheme = hop.Proto
+		}
+
+		if hop.Host != "" {
+			rtn.Host = hop.Host
+		}
+
+		if hop.For != "" {
+			rtn.ClientIP = hop.For
+		}
+
+		// Stop once we've reached a hop that was not made by one of our proxies
+		if !proxies.Contains(rtn.ClientIP) {
+			break
+		}
+	}
+
+	return rtn
+}
@@ -0,0 +1,78 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Reverse Proxy Feature Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of forwarded.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in forwarded.go
+*/
+
+package mvcapp_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestParseTrustedProxies ensures that mvcapp.ParseTrustedProxies returns the expected values
+func TestParseTrustedProxies(t *testing.T) {
+	proxies, err := mvcapp.ParseTrustedProxies([]string{"10.0.0.0/8", "192.168.1.10", "::1"})
+	if err != nil {
+		t.Fatalf("Failed to parse trusted proxies: %s", err)
+	}
+
+	if !proxies.Contains("10.1.2.3") || !proxies.Contains("192.168.1.10") || !proxies.Contains("::1") {
+		t.Error("Failed to contain trusted proxy addresses")
+	}
+
+	if proxies.Contains("192.168.1.11") || proxies.Contains("not an ip") {
+		t.Error("Failed to refuse untrusted addresses")
+	}
+
+	if _, err := mvcapp.ParseTrustedProxies([]string{"10.0.0.0/99"}); err == nil {
+		t.Error("Failed to fail parsing an invalid network")
+	}
+
+	if _, err := mvcapp.ParseTrustedProxies([]string{"proxy.local"}); err == nil {
+		t.Error("Failed to fail parsing an invalid address")
+	}
+}
+
+// TestTrustedProxies_Resolve ensures that the TrustedProxies.Resolve method honors the Forwarded and
+// X-Forwarded-* headers of trusted proxies only
+func TestTrustedProxies_Resolve(t *testing.T) {
+	proxies, _ := mvcapp.ParseTrustedProxies([]string{"10.0.0.0/8"})
+
+	// Direct request, forwarding headers are ignored
+	req := httptest.NewRequest("GET", "http://example.com/", nil)
+	req.RemoteAddr = "203.0.113.5:5000"
+	req.Header.Set("X-Forwarded-For", "1.2.3.4")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	res := proxies.Resolve(req)
+	if res.ClientIP != "203.0.113.5" || res.Scheme != "http" || res.Host != "example.com" {
+		t.Errorf("Failed to ignore headers from an untrusted address: %+v", res)
+	}
+
+	// X-Forwarded-* through two trusted hops (load balancer then nginx)
+	req = httptest.NewRequest("GET", "http://backend:8080/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("X-Forwarded-For", "6.6.6.6, 198.51.100.7, 10.0.0.1")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "example.com")
+	res = proxies.Resolve(req)
+	if res.ClientIP != "198.51.100.7" || res.Scheme != "https" || res.Host != "example.com" {
+		t.Errorf("Failed to resolve X-Forwarded-* headers: %+v", res)
+	}
+
+	// RFC 7239 Forwarded header takes precedence
+	req = httptest.NewRequest("GET", "http://backend:8080/", nil)
+	req.RemoteAddr = "10.0.0.2:5000"
+	req.Header.Set("Forwarded", "for=\"[2001:db8::1]:4711\";proto=https;host=www.example.com, for=10.0.0.1;proto=http")
+	req.Header.Set("X-Forwarded-For", "6.6.6.6")
+	res = proxies.Resolve(req)
+	if res.ClientIP != "2001:db8::1" || res.Scheme != "https" || res.Host != "www.example.com" {
+		t.Errorf("Failed to resolve the Forwarded header: %+v", res)
+	}
+}
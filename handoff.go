@@ -0,0 +1,240 @@
+/*
+	Digivance MVC Application Framework
+	Listener Handoff Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the zero downtime restart functionality. On restart the running application
+	starts a new copy of the executable which inherits the listening sockets through file descriptors.
+	Once the new process signals that it is ready, the old process drains its in flight requests
+	and exits, so no connections are dropped during a release.
+*/
+
+package mvcapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Environment variables used to pass the listening sockets and the readiness pipe to the new process
+const (
+	// handoffFDsEnv holds the number of listening sockets passed to the new process
+	handoffFDsEnv = "MVCAPP_LISTEN_FDS"
+
+	// handoffNamesEnv holds the json encoded listener keys of the passed sockets (in order)
+	handoffNamesEnv = "MVCAPP_LISTEN_FDNAMES"
+
+	// handoffReadyEnv holds the file descriptor that the new process writes to once it is ready
+	handoffReadyEnv = "MVCAPP_READY_FD"
+)
+
+// inheritedListeners caches the sockets handed off by the previous process
+var (
+	inheritedListeners     map[string]net.Listener
+	inheritedListenersOnce sync.Once
+)
+
+// loadInheritedListeners reads the handoff environment variables and wraps the inherited file
+// descriptors as net.Listeners keyed by their listener key
+func loadInheritedListeners() {
+	inheritedListeners = make(map[string]net.Listener, 0)
+
+	count, err := strconv.Atoi(os.Getenv(handoffFDsEnv))
+	if err != nil || count <= 0 {
+		return
+	}
+
+	names := []string{}
+	if err := json.Unmarshal([]byte(os.Getenv(handoffNamesEnv)), &names); err != nil {
+		LogErrorf("Failed to read inherited listener names: %s", err)
+		return
+	}
+
+	os.Unsetenv(handoffFDsEnv)
+	os.Unsetenv(handoffNamesEnv)
+	inheritedListeners = listenersFromFiles(count, names)
+}
+
+// inheritedListener returns (and claims) the socket handed off by the previous process for the
+// provided listener key, or nil if there is none
+func inheritedListener(key string) net.Listener {
+	inheritedListenersOnce.Do(loadInheritedListeners)
+
+	rtn := inheritedListeners[key]
+	delete(inheritedListeners, key)
+	return rtn
+}
+
+// IsRestarted returns true if this process was started by Restart and has not yet signaled
+// that it is ready
+func IsRestarted() bool {
+	return os.Getenv(handoffReadyEnv) != ""
+}
+
+// NotifyReady tells the previous process (if this process was started by Restart) that all of
+// the listeners are open, so it can drain its requests and exit. RunListeners calls this for you
+func NotifyReady() error {
+	value := os.Getenv(handoffReadyEnv)
+	if value == "" {
+		return nil
+	}
+
+	os.Unsetenv(handoffReadyEnv)
+
+	fd, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("Failed to parse readiness file descriptor: %s", err)
+	}
+
+	pipe := os.NewFile(uintptr(fd), "ready")
+	defer pipe.Close()
+
+	if _, err := pipe.Write([]byte{1}); err != nil {
+		return fmt.Errorf("Failed to signal readiness to previous process: %s", err)
+	}
+
+	LogTrace("Signaled readiness to previous process")
+	return nil
+}
+
+// Restart starts a new copy of the executable that inherits the sockets of the running
+// Listeners. Once the new process signals it is ready, the listeners are gracefully shut down
+// (causing RunListeners to return nil once in flight requests have drained). If the new process
+// fails to become ready within RestartTimeout, it is killed and this process continues serving.
+// Only the Listeners are handed off, so Restart only works for applications hosted with
+// RunListeners and returns an error for the servers started by Run and the RunSecure methods
+func (app *Application) Restart() error {
+	if app.HTTPServer != nil || app.HTTPSServer != nil {
+		return errors.New("Can not restart, only applications hosted with RunListeners can be restarted")
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Failed to locate executable for restart: %s", err)
+	}
+
+	names := []string{}
+	files := []*os.File{}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	for _, listener := range app.Listeners {
+		socket, ok := listener.Socket().(interface{ File() (*os.File, error) })
+		if !ok {
+			continue
+		}
+
+		file, err := socket.File()
+		if err != nil {
+			return fmt.Errorf("Failed to hand off listener %s: %s", listener.Name, err)
+		}
+
+		names = append(names, listener.handoffKey())
+		files = append(files, file)
+	}
+
+	if len(files) <= 0 {
+		return errors.New("Can not restart, no running listeners to hand off")
+	}
+
+	encodedNames, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	ready, readyWriter, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+	defer ready.Close()
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = append(files, readyWriter)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("%s=%d", handoffFDsEnv, len(files)),
+		fmt.Sprintf("%s=%s", handoffNamesEnv, encodedNames),
+		fmt.Sprintf("%s=%d", handoffReadyEnv, 3+len(files)),
+	)
+
+	err = cmd.Start()
+	readyWriter.Close()
+	if err != nil {
+		return fmt.Errorf("Failed to start new process: %s", err)
+	}
+
+	LogMessagef("Started new process %d, waiting for it to become ready", cmd.Process.Pid)
+
+	// The read returns once the new process writes to (or closes) its end of the pipe
+	timeout := time.Duration(app.Config.RestartTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	ready.SetReadDeadline(time.Now().Add(timeout))
+	if n, err := ready.Read(make([]byte, 1)); n <= 0 || err != nil {
+		cmd.Process.Kill()
+		cmd.Wait()
+		return fmt.Errorf("New process failed to become ready: %v", err)
+	}
+
+	// The new process now owns the unix socket paths, don't remove them as we close
+	for _, listener := range app.Listeners {
+		if socket, ok := listener.Socket().(*net.UnixListener); ok {
+			socket.SetUnlinkOnClose(false)
+		}
+	}
+
+	cmd.Process.Release()
+	LogMessage("New process is ready, draining in flight requests")
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	for _, listener := range app.Listeners {
+		if err := listener.Shutdown(ctx); err != nil {
+			LogWarningf("Failed to drain listener %s: %s", listener.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// HandleRestartSignals starts a goroutine that calls Restart whenever the process receives one
+// of the restart signals (SIGHUP or SIGUSR2 where supported)
+func (app *Application) HandleRestartSignals() {
+	if len(restartSignals) <= 0 {
+		LogWarning("Restart signals are not supported on this platform")
+		return
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, restartSignals...)
+
+	go func() {
+		for sig := range signals {
+			LogMessagef("Received %s, restarting", sig)
+			if err := app.Restart(); err != nil {
+				LogErrorf("Failed to restart: %s", err)
+				continue
+			}
+
+			signal.Stop(signals)
+			return
+		}
+	}()
+}
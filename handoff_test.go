@@ -0,0 +1,107 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Listener Handoff Feature Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of handoff.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in handoff.go
+*/
+
+package mvcapp_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestNotifyReady ensures that mvcapp.NotifyReady does nothing when the process was not restarted
+func TestNotifyReady(t *testing.T) {
+	if mvcapp.IsRestarted() {
+		t.Fatal("Failed to detect that the test process was not restarted")
+	}
+
+	if err := mvcapp.NotifyReady(); err != nil {
+		t.Errorf("Failed to ignore readiness without a previous process: %s", err)
+	}
+}
+
+// TestApplication_Restart ensures that the Application.Restart method refuses to restart without running
+// listeners
+func TestApplication_Restart(t *testing.T) {
+	app := mvcapp.NewApplication()
+	app.AddListener(mvcapp.NewListener("idle", mvcapp.ListenerNetworkTCP, "127.0.0.1:0"))
+
+	if err := app.Restart(); err == nil {
+		t.Error("Failed to prevent restarting without running listeners")
+	}
+
+	app.HTTPServer = &http.Server{}
+	if err := app.Restart(); err == nil || !strings.Contains(err.Error(), "RunListeners") {
+		t.Errorf("Failed to refuse restarting an application hosted with Run: %v", err)
+	}
+}
+
+// TestListener_Shutdown ensures that the Listener.Serve method waits for in flight requests to drain
+// after a Shutdown, and then returns nil
+func TestListener_Shutdown(t *testing.T) {
+	listener := mvcapp.NewListener("drain", mvcapp.ListenerNetworkTCP, "127.0.0.1:0")
+	if err := listener.Open(); err != nil {
+		t.Fatalf("Failed to open listener: %s", err)
+	}
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("drained"))
+	})
+
+	served := make(chan error, 1)
+	go func() {
+		served <- listener.Serve(handler)
+	}()
+
+	responses := make(chan error, 1)
+	go func() {
+		res, err := http.Get("http://" + listener.Socket().Addr().String() + "/")
+		if err == nil {
+			res.Body.Close()
+		}
+		responses <- err
+	}()
+
+	<-started
+	if err := listener.Shutdown(context.Background()); err != nil {
+		t.Errorf("Failed to shut down listener: %s", err)
+	}
+
+	if err := <-responses; err != nil {
+		t.Errorf("Failed to complete in flight request: %s", err)
+	}
+
+	if err := <-served; err != nil {
+		t.Errorf("Failed to return nil from a gracefully stopped listener: %s", err)
+	}
+
+	// The socket of a listener that was opened but never served is closed
+	listener = mvcapp.NewListener("idle", mvcapp.ListenerNetworkTCP, "127.0.0.1:0")
+	if err := listener.Open(); err != nil {
+		t.Fatalf("Failed to open listener: %s", err)
+	}
+
+	address := listener.Socket().Addr().String()
+	if err := listener.Shutdown(context.Background()); err != nil {
+		t.Errorf("Failed to shut down idle listener: %s", err)
+	}
+
+	if conn, err := net.Dial("tcp", address); err == nil {
+		conn.Close()
+		t.Error("Failed to close the socket of an idle listener")
+	}
+}
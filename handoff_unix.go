@@ -0,0 +1,19 @@
+//go:build !windows
+
+/*
+	Digivance MVC Application Framework
+	Listener Handoff Signals (Unix)
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the signals that trigger a zero downtime restart on unix platforms
+*/
+
+package mvcapp
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignals are the signals that HandleRestartSignals responds to
+var restartSignals = []os.Signal{syscall.SIGHUP, syscall.SIGUSR2}
@@ -0,0 +1,17 @@
+//go:build windows
+
+/*
+	Digivance MVC Application Framework
+	Listener Handoff Signals (Windows)
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the signals that trigger a zero downtime restart on windows, where passing
+	sockets to a new process is not supported
+*/
+
+package mvcapp
+
+import "os"
+
+// restartSignals are the signals that HandleRestartSignals responds to
+var restartSignals = []os.Signal{}
@@ -0,0 +1,324 @@
+/*
+	Digivance MVC Application Framework
+	Listener Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the listener object. Listeners allow a single application to serve several
+	addresses at once (such as a public port and an internal admin port) over TCP, Unix domain
+	sockets or sockets inherited through systemd socket activation, each with its own TLS settings
+	and subset of the registered controllers.
+*/
+
+package mvcapp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Listener network types
+const (
+	// ListenerNetworkTCP listens on a TCP/IP "host:port" address
+	ListenerNetworkTCP = "tcp"
+
+	// ListenerNetworkUnix listens on a Unix domain socket path
+	ListenerNetworkUnix = "unix"
+
+	// ListenerNetworkSystemd uses a socket inherited through systemd socket activation, the
+	// address is the FileDescriptorName of the socket unit or its index (E.g. "0")
+	ListenerNetworkSystemd = "systemd"
+)
+
+// Listener defines a single address that the application serves requests on
+type Listener struct {
+	// Name is used to identify this listener in log messages
+	Name string
+
+	// Network is the type of socket to listen on (tcp, unix or systemd)
+	Network string
+
+	// Address is the "host:port" for tcp, the socket path for unix or the socket name (or index)
+	// for systemd listeners
+	Address string
+
+	// SocketMode is the file permissions applied to unix domain sockets (E.g. 0660)
+	SocketMode os.FileMode
+
+	// TLSCertFile is the full path and filename of the TLS Certificate file, enables https
+	TLSCertFile string
+
+	// TLSKeyFile is the full path and filename of the TLS Key file
+	TLSKeyFile string
+
+	// ForceSecure will redirect all requests on this listener to https (see RedirectSecure)
+	ForceSecure bool
+
+	// Controllers is the subset of registered controller names served by this listener, all
+	// controllers are served if empty
+	Controllers []string
+
+	// TLSConfig is an optional TLS configuration, enables https when set
+	TLSConfig *tls.Config `json:"-"`
+
+	// Server is the http.Server hosting this listener while running
+	Server *http.Server `json:"-"`
+
+	// mutex protects the socket, server and drained members across goroutines
+	mutex sync.Mutex
+
+	// socket is the open socket of this listener (see Open)
+	socket net.Listener
+
+	// drained is closed once a Shutdown has finished waiting for in flight requests
+	drained chan struct{}
+}
+
+// NewListener returns a new Listener for the provided network and address
+func NewListener(name string, network string, address string) *Listener {
+	return &Listener{
+		Name:        name,
+		Network:     network,
+		Address:     address,
+		SocketMode:  0660,
+		Controllers: []string{},
+	}
+}
+
+// IsSecure returns true if this listener serves https
+func (listener *Listener) IsSecure() bool {
+	return listener.TLSConfig != nil || (listener.TLSCertFile != "" && listener.TLSKeyFile != "")
+}
+
+// handoffKey identifies this listeners socket when it is handed off to a new process
+func (listener *Listener) handoffKey() string {
+	return fmt.Sprintf("%s|%s", strings.ToLower(listener.Network), listener.Address)
+}
+
+// Listen opens the socket defined by this listeners network and address. If the socket was
+// handed off by the previous process during a restart (see Application.Restart) it is reused
+func (listener *Listener) Listen() (net.Listener, error) {
+	if socket := inheritedListener(listener.handoffKey()); socket != nil {
+		LogTracef("Listener %s using socket inherited from previous process", listener.Name)
+		return socket, nil
+	}
+
+	switch strings.ToLower(listener.Network) {
+	case "", ListenerNetworkTCP, "tcp4", "tcp6":
+		network := listener.Network
+		if network == "" {
+			network = ListenerNetworkTCP
+		}
+
+		return net.Listen(network, listener.Address)
+
+	case ListenerNetworkUnix:
+		path := listener.Address
+		if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "./") {
+			path = GetApplicationPath() + path[1:]
+		}
+
+		// Remove a stale socket left behind by an unclean shutdown
+		if fi, err := os.Stat(path); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(path)
+		}
+
+		rtn, err := net.Listen(ListenerNetworkUnix, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if listener.SocketMode != 0 {
+			if err := os.Chmod(path, listener.SocketMode); err != nil {
+				rtn.Close()
+				return nil, fmt.Errorf("Failed to set unix socket permissions: %s", err)
+			}
+		}
+
+		return rtn, nil
+
+	case ListenerNetworkSystemd:
+		return SystemdListener(listener.Address)
+	}
+
+	return nil, fmt.Errorf("Failed to listen, unknown network type: %s", listener.Network)
+}
+
+// Open opens this listeners socket (see Listen) without serving it yet, this allows the
+// application to confirm every socket is listening before accepting requests
+func (listener *Listener) Open() error {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+
+	if listener.socket != nil {
+		return nil
+	}
+
+	socket, err := listener.Listen()
+	if err != nil {
+		return err
+	}
+
+	listener.socket = socket
+	return nil
+}
+
+// Socket returns the open socket of this listener, or nil if it is not open
+func (listener *Listener) Socket() net.Listener {
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+
+	return listener.socket
+}
+
+// Serve opens (if needed) this listeners socket and serves the provided handler until the
+// server is shut down. After a Shutdown, Serve waits for in flight requests to complete and
+// returns nil
+func (listener *Listener) Serve(handler http.Handler) error {
+	if err := listener.Open(); err != nil {
+		return err
+	}
+
+	listener.mutex.Lock()
+	if listener.Server != nil {
+		listener.mutex.Unlock()
+		return fmt.Errorf("Can not serve listener %s, Server already in use", listener.Name)
+	}
+
+	server := &http.Server{Handler: handler, TLSConfig: listener.TLSConfig}
+	socket := listener.socket
+	drained := make(chan struct{})
+	listener.Server = server
+	listener.drained = drained
+	listener.mutex.Unlock()
+
+	LogTracef("Listener %s serving on %s %s", listener.Name, listener.Network, listener.Address)
+
+	var err error
+	if listener.IsSecure() {
+		err = server.ServeTLS(socket, listener.TLSCertFile, listener.TLSKeyFile)
+	} else {
+		err = server.Serve(socket)
+	}
+
+	if err == http.ErrServerClosed {
+		<-drained
+		return nil
+	}
+
+	return err
+}
+
+// Shutdown gracefully stops this listener, waiting for in flight requests to complete. The
+// socket of a listener that was opened but not served yet is closed
+func (listener *Listener) Shutdown(ctx context.Context) error {
+	listener.mutex.Lock()
+	server := listener.Server
+	socket := listener.socket
+	drained := listener.drained
+	listener.Server = nil
+	listener.socket = nil
+	listener.mutex.Unlock()
+
+	if server == nil {
+		if socket != nil {
+			return socket.Close()
+		}
+
+		return nil
+	}
+
+	err := server.Shutdown(ctx)
+	close(drained)
+	return err
+}
+
+// systemdListeners caches the sockets inherited through systemd socket activation, these can
+// only be claimed once per process
+var (
+	systemdListeners     map[string]net.Listener
+	systemdListenersOnce sync.Once
+	systemdListenersErr  error
+)
+
+// listenersFromFiles wraps count inherited file descriptors, starting at 3, as net.Listeners
+// keyed by both their index and the provided names
+func listenersFromFiles(count int, names []string) map[string]net.Listener {
+	rtn := make(map[string]net.Listener, 0)
+
+	for i := 0; i < count; i++ {
+		fd := uintptr(3 + i)
+		name := strconv.Itoa(i)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+
+		file := os.NewFile(fd, name)
+		socket, err := net.FileListener(file)
+		file.Close()
+		if err != nil {
+			LogWarningf("Failed to use inherited socket %s: %s", name, err)
+			continue
+		}
+
+		rtn[strconv.Itoa(i)] = socket
+		if name != strconv.Itoa(i) {
+			rtn[name] = socket
+		}
+	}
+
+	return rtn
+}
+
+// loadSystemdListeners reads the LISTEN_PID, LISTEN_FDS and LISTEN_FDNAMES environment variables
+// and wraps the inherited file descriptors (starting at 3) as net.Listeners
+func loadSystemdListeners() {
+	systemdListeners = make(map[string]net.Listener, 0)
+
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		systemdListenersErr = errors.New("No sockets were passed by systemd socket activation")
+		return
+	}
+
+	count, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || count <= 0 {
+		systemdListenersErr = errors.New("No sockets were passed by systemd socket activation")
+		return
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	// Prevent child processes from believing these sockets were passed to them
+	os.Unsetenv("LISTEN_PID")
+	os.Unsetenv("LISTEN_FDS")
+	os.Unsetenv("LISTEN_FDNAMES")
+
+	systemdListeners = listenersFromFiles(count, names)
+}
+
+// SystemdListener returns the socket inherited through systemd socket activation by its
+// FileDescriptorName or index
+func SystemdListener(name string) (net.Listener, error) {
+	systemdListenersOnce.Do(loadSystemdListeners)
+	if systemdListenersErr != nil {
+		return nil, systemdListenersErr
+	}
+
+	if name == "" {
+		name = "0"
+	}
+
+	if rtn, ok := systemdListeners[name]; ok {
+		return rtn, nil
+	}
+
+	return nil, fmt.Errorf("No systemd socket found named: %s", name)
+}
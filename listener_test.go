@@ -0,0 +1,140 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Listener Feature Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of listener.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in listener.go
+*/
+
+package mvcapp_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// unixSocketClient is used internally to make http requests over the provided unix socket path
+func unixSocketClient(path string) *http.Client {
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+				return (&net.Dialer{}).DialContext(ctx, "unix", path)
+			},
+		},
+	}
+}
+
+// TestListener_Listen ensures that the Listener.Listen method operates as expected for each network
+func TestListener_Listen(t *testing.T) {
+	listener := mvcapp.NewListener("tcp", mvcapp.ListenerNetworkTCP, "127.0.0.1:0")
+	socket, err := listener.Listen()
+	if err != nil {
+		t.Fatalf("Failed to listen on tcp: %s", err)
+	}
+	socket.Close()
+
+	path := fmt.Sprintf("%s/_test_listener.sock", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(path)
+
+	listener = mvcapp.NewListener("unix", mvcapp.ListenerNetworkUnix, path)
+	listener.SocketMode = 0600
+	socket, err = listener.Listen()
+	if err != nil {
+		t.Fatalf("Failed to listen on unix socket: %s", err)
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil || fi.Mode().Perm() != 0600 {
+		t.Error("Failed to set unix socket permissions")
+	}
+	socket.Close()
+
+	listener = mvcapp.NewListener("systemd", mvcapp.ListenerNetworkSystemd, "web")
+	if _, err := listener.Listen(); err == nil {
+		t.Error("Failed to fail listening without systemd socket activation")
+	}
+
+	listener = mvcapp.NewListener("bogus", "carrier-pigeon", "")
+	if _, err := listener.Listen(); err == nil {
+		t.Error("Failed to fail listening on an unknown network")
+	}
+}
+
+// TestApplication_RunListeners ensures that the Application.RunListeners method serves each listener with
+// its own subset of controllers
+func TestApplication_RunListeners(t *testing.T) {
+	app := mvcapp.NewApplication()
+	if err := app.RunListeners(); err == nil {
+		t.Error("Failed to fail running without listeners")
+	}
+
+	app.RouteManager.RegisterController("Home", newTestController)
+	app.RouteManager.RegisterController("Admin", newTestController)
+	app.RouteManager.DefaultController = "Admin"
+
+	publicPath := fmt.Sprintf("%s/_test_public.sock", mvcapp.GetApplicationPath())
+	adminPath := fmt.Sprintf("%s/_test_admin.sock", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(publicPath)
+	defer os.RemoveAll(adminPath)
+
+	public := mvcapp.NewListener("public", mvcapp.ListenerNetworkUnix, publicPath)
+	public.Controllers = []string{"Home"}
+	app.AddListener(public)
+	app.AddListener(mvcapp.NewListener("admin", mvcapp.ListenerNetworkUnix, adminPath))
+
+	go app.RunListeners()
+	defer app.Stop()
+
+	for i := 0; i < 50; i++ {
+		_, adminErr := os.Stat(adminPath)
+		_, publicErr := os.Stat(publicPath)
+		if adminErr == nil && publicErr == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if len(app.RouteManager.Subset(public.Controllers).Routes) != 1 {
+		t.Error("Failed to limit the public listener to its controllers")
+	}
+
+	res, err := unixSocketClient(adminPath).Get("http://admin/admin/index")
+	if err != nil {
+		t.Fatalf("Failed to request from admin listener: %s", err)
+	}
+
+	data, _ := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if res.StatusCode != 200 || len(data) <= 0 {
+		t.Errorf("Failed to serve admin controller, received status %d", res.StatusCode)
+	}
+
+	res, err = unixSocketClient(publicPath).Get("http://public/home/index")
+	if err != nil {
+		t.Fatalf("Failed to request from public listener: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != 200 {
+		t.Errorf("Failed to serve home controller, received status %d", res.StatusCode)
+	}
+
+	res, err = unixSocketClient(publicPath).Get("http://public/admin/index")
+	if err != nil {
+		t.Fatalf("Failed to request from public listener: %s", err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusNotFound {
+		t.Errorf("Failed to hide the admin controller from the public listener, received status %d", res.StatusCode)
+	}
+}
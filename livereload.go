@@ -0,0 +1,121 @@
+/*
+	Digivance MVC Application Framework
+	Live Reload Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the development mode live reload endpoint. Pages include a small script (see
+	the {{ LiveReload }} view function) that listens to the endpoint using server sent events, and
+	the browser refreshes whenever the watcher reports a change.
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"sync"
+)
+
+// LiveReload tells the connected browsers to refresh the page
+type LiveReload struct {
+	// Path is the url path of the server sent events endpoint
+	Path string
+
+	// mutex protects the clients
+	mutex sync.Mutex
+
+	// clients are the notification channels of the connected browsers
+	clients map[chan string]bool
+}
+
+// NewLiveReload returns a new LiveReload served at /_mvcapp/livereload
+func NewLiveReload() *LiveReload {
+	return &LiveReload{
+		Path:    "/_mvcapp/livereload",
+		clients: make(map[chan string]bool, 0),
+	}
+}
+
+// Notify tells every connected browser to refresh, the reason is sent as the event data
+func (liveReload *LiveReload) Notify(reason string) {
+	liveReload.mutex.Lock()
+	defer liveReload.mutex.Unlock()
+
+	for client := range liveReload.clients {
+		select {
+		case client <- reason:
+		default:
+			// The browser already has a pending refresh
+		}
+	}
+}
+
+// Close disconnects every connected browser, used when the application is stopping so the
+// open connections don't hold up the graceful shutdown
+func (liveReload *LiveReload) Close() {
+	liveReload.mutex.Lock()
+	defer liveReload.mutex.Unlock()
+
+	for client := range liveReload.clients {
+		close(client)
+		delete(liveReload.clients, client)
+	}
+}
+
+// Clients returns the number of connected browsers
+func (liveReload *LiveReload) Clients() int {
+	liveReload.mutex.Lock()
+	defer liveReload.mutex.Unlock()
+
+	return len(liveReload.clients)
+}
+
+// Script returns the script tag that connects the page to the live reload endpoint
+func (liveReload *LiveReload) Script() template.HTML {
+	return template.HTML(fmt.Sprintf(
+		"<script type=\"text/javascript\">new EventSource('%s').addEventListener('reload',function(){window.location.reload();});</script>",
+		template.JSEscapeString(liveReload.Path)))
+}
+
+// ServeLiveReload serves the server sent events endpoint, returns false if the request was not
+// made to the live reload Path
+func (liveReload *LiveReload) ServeLiveReload(response http.ResponseWriter, request *http.Request) bool {
+	if request.URL.Path != liveReload.Path {
+		return false
+	}
+
+	flusher, ok := response.(http.Flusher)
+	if !ok {
+		http.Error(response, "Live reload is not supported by this server", http.StatusInternalServerError)
+		return true
+	}
+
+	client := make(chan string, 1)
+	liveReload.mutex.Lock()
+	liveReload.clients[client] = true
+	liveReload.mutex.Unlock()
+
+	defer func() {
+		liveReload.mutex.Lock()
+		delete(liveReload.clients, client)
+		liveReload.mutex.Unlock()
+	}()
+
+	response.Header().Set("Content-Type", "text/event-stream")
+	response.Header().Set("Cache-Control", "no-cache")
+	response.WriteHeader(http.StatusOK)
+	fmt.Fprint(response, ": connected\n\n")
+	flusher.Flush()
+
+	select {
+	case reason, ok := <-client:
+		if ok {
+			fmt.Fprintf(response, "event: reload\ndata: %s\n\n", reason)
+			flusher.Flush()
+		}
+	case <-request.Context().Done():
+	}
+
+	return true
+}
@@ -0,0 +1,77 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Live Reload Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of livereload.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in livereload.go
+*/
+
+package mvcapp_test
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestLiveReload_Notify ensures that connected browsers receive a reload event when notified
+func TestLiveReload_Notify(t *testing.T) {
+	liveReload := mvcapp.NewLiveReload()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !liveReload.ServeLiveReload(w, r) {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + liveReload.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected content type: %s", res.Header.Get("Content-Type"))
+	}
+
+	for i := 0; i < 100 && liveReload.Clients() <= 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	liveReload.Notify("bundle")
+
+	reader := bufio.NewReader(res.Body)
+	body := ""
+	for !strings.Contains(body, "data: bundle") {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read reload event, received: %s (%s)", body, err)
+		}
+
+		body += line
+	}
+
+	if !strings.Contains(body, "event: reload") {
+		t.Fatalf("Expected a reload event, received: %s", body)
+	}
+}
+
+// TestController_LiveReloadScript ensures the LiveReload view function is only rendered when
+// live reload is enabled
+func TestController_LiveReloadScript(t *testing.T) {
+	controller := &mvcapp.Controller{}
+	if controller.LiveReloadScript() != "" {
+		t.Fatal("Expected no script when live reload is disabled")
+	}
+
+	controller.LiveReload = mvcapp.NewLiveReload()
+	if !strings.Contains(string(controller.LiveReloadScript()), "/_mvcapp/livereload") {
+		t.Fatalf("Unexpected live reload script: %s", controller.LiveReloadScript())
+	}
+}
@@ -0,0 +1,693 @@
+/*
+	Digivance MVC Application Framework
+	Localization Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the localization features. The Localizer holds the message catalogs of the
+	supported cultures, loaded from <culture>.json or <culture>.po files in the locales folder, and
+	detects the culture of each request from a route prefix (E.g. /fr/home/index), the culture query
+	string value, the culture cookie or the Accept-Language header, in that order. Controllers
+	expose the detected Culture, views translate messages with {{ T "key" args }}, numbers and
+	dates are formatted following the culture and localized views (E.g. index.fr.htm) are used in
+	place of the neutral view when they exist.
+
+	JSON catalogs map keys to messages, or to their plural forms by plural category:
+
+		{ "Welcome": "Bienvenue {0}", "CartItems": { "one": "{0} article", "other": "{0} articles" } }
+
+	Messages are formatted by replacing {0}, {1}, ... with the arguments, the first argument
+	selecting the plural form when it is a number (see PluralCategory).
+*/
+
+package mvcapp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sources of the detected culture of a request (see Localizer.DetectCulture)
+const (
+	CultureSourceRoute   = "route"
+	CultureSourceQuery   = "query"
+	CultureSourceCookie  = "cookie"
+	CultureSourceHeader  = "header"
+	CultureSourceDefault = "default"
+)
+
+// cultureContextKey is the request context key of the detected culture
+type cultureContextKey struct{}
+
+// cultureSelection is the detected culture of a request and its source
+type cultureSelection struct {
+	culture string
+	source  string
+}
+
+// Localizer holds the message catalogs of the supported cultures and detects the culture of
+// requests
+type Localizer struct {
+	// Cultures are the supported cultures, the first being the default culture
+	Cultures []string
+
+	// Path is the full path of the folder the message catalogs are loaded from (see LoadDirectory)
+	Path string
+
+	// CookieName is the name of the cookie that holds the users culture (empty to disable)
+	CookieName string
+
+	// QueryKey is the query string key that selects the culture (empty to disable)
+	QueryKey string
+
+	// mutex protects the catalogs
+	mutex sync.RWMutex
+
+	// catalogs are the messages by culture and key, each message being its plural forms by
+	// plural category (PluralOther for messages without plural forms)
+	catalogs map[string]map[string]map[string]string
+}
+
+// NewLocalizer returns a new Localizer of the provided supported cultures, the first being the
+// default culture
+func NewLocalizer(cultures ...string) *Localizer {
+	rtn := &Localizer{
+		Cultures:   []string{},
+		CookieName: "mvcapp.culture",
+		QueryKey:   "culture",
+		catalogs:   make(map[string]map[string]map[string]string, 0),
+	}
+
+	for _, culture := range cultures {
+		if culture = NormalizeCulture(culture); culture != "" {
+			rtn.Cultures = append(rtn.Cultures, culture)
+		}
+	}
+
+	return rtn
+}
+
+// NewLocalizerFromConfig returns a new Localizer populated from the provided configuration, with
+// the message catalogs of the LocalesPath loaded. Returns nil when no Cultures are configured
+func NewLocalizerFromConfig(config *ConfigurationManager) *Localizer {
+	if len(config.Cultures) <= 0 {
+		return nil
+	}
+
+	rtn := NewLocalizer(config.Cultures...)
+	rtn.CookieName = config.CultureCookieName
+	rtn.QueryKey = config.CultureQueryKey
+	if config.LocalesPath != "" {
+		if err := rtn.LoadDirectory(config.LocalesPath); err != nil && !os.IsNotExist(err) {
+			LogErrorf("Failed to load message catalogs: %s", err)
+		}
+	}
+
+	return rtn
+}
+
+// DefaultCulture returns the default culture, the first of the supported Cultures
+func (localizer *Localizer) DefaultCulture() string {
+	if localizer == nil || len(localizer.Cultures) <= 0 {
+		return ""
+	}
+
+	return localizer.Cultures[0]
+}
+
+// SetMessage adds (or replaces) the message of the provided key in the catalog of the provided
+// culture, forms are the plural forms of the message by plural category
+func (localizer *Localizer) SetMessage(culture string, key string, forms map[string]string) {
+	culture = NormalizeCulture(culture)
+
+	localizer.mutex.Lock()
+	defer localizer.mutex.Unlock()
+
+	catalog := localizer.catalogs[culture]
+	if catalog == nil {
+		catalog = make(map[string]map[string]string, 0)
+		localizer.catalogs[culture] = catalog
+	}
+
+	catalog[key] = forms
+}
+
+// LoadJSON loads the provided json message catalog of the provided culture
+func (localizer *Localizer) LoadJSON(culture string, data []byte) error {
+	messages := make(map[string]interface{}, 0)
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return err
+	}
+
+	for key, value := range messages {
+		switch v := value.(type) {
+		case string:
+			localizer.SetMessage(culture, key, map[string]string{PluralOther: v})
+		case map[string]interface{}:
+			forms := make(map[string]string, len(v))
+			for category, text := range v {
+				s, ok := text.(string)
+				if !ok {
+					return fmt.Errorf("Invalid plural form %s of message %s", category, key)
+				}
+
+				forms[category] = s
+			}
+
+			localizer.SetMessage(culture, key, forms)
+		default:
+			return fmt.Errorf("Invalid message %s, expected a string or plural forms", key)
+		}
+	}
+
+	return nil
+}
+
+// poString returns the value of the provided quoted PO file string
+func poString(text string) (string, error) {
+	return strconv.Unquote(strings.TrimSpace(text))
+}
+
+// LoadPO loads the provided gettext PO message catalog of the provided culture. The msgid is
+// the message key and the msgstr[n] plural forms follow the order of the Categories of the
+// cultures plural rule. Fuzzy and untranslated entries are skipped
+func (localizer *Localizer) LoadPO(culture string, data []byte) error {
+	categories := GetPluralRule(culture).Categories
+	id, field, fuzzy := "", "", false
+	forms := make(map[string]string, 0)
+
+	save := func() {
+		// Untranslated forms are not used
+		for category, text := range forms {
+			if text == "" {
+				delete(forms, category)
+			}
+		}
+
+		if id != "" && !fuzzy && len(forms) > 0 {
+			localizer.SetMessage(culture, id, forms)
+		}
+
+		id, field, fuzzy = "", "", false
+		forms = make(map[string]string, 0)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case text == "":
+			save()
+
+		case strings.HasPrefix(text, "#,"):
+			fuzzy = fuzzy || strings.Contains(text, "fuzzy")
+
+		case strings.HasPrefix(text, "#"):
+			// Comments are ignored
+
+		case strings.HasPrefix(text, "\""):
+			value, err := poString(text)
+			if err != nil {
+				return fmt.Errorf("Invalid PO string on line %d: %s", line, err)
+			}
+
+			if field == "msgid" {
+				id += value
+			} else if field != "" && field != "msgid_plural" && field != "msgctxt" {
+				forms[field] += value
+			}
+
+		default:
+			parts := strings.SplitN(text, " ", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("Invalid PO entry on line %d", line)
+			}
+
+			value, err := poString(parts[1])
+			if err != nil {
+				return fmt.Errorf("Invalid PO string on line %d: %s", line, err)
+			}
+
+			keyword := parts[0]
+			switch {
+			case keyword == "msgid":
+				if id != "" || len(forms) > 0 {
+					save()
+				}
+
+				field, id = keyword, value
+			case keyword == "msgid_plural" || keyword == "msgctxt":
+				field = keyword
+			case keyword == "msgstr":
+				field = PluralOther
+				forms[field] = value
+			case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+				index, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+				if err != nil || index < 0 {
+					return fmt.Errorf("Invalid plural form on line %d", line)
+				}
+
+				field = PluralOther
+				if index < len(categories) {
+					field = categories[index]
+				}
+
+				forms[field] = value
+			default:
+				return fmt.Errorf("Unknown PO keyword %s on line %d", keyword, line)
+			}
+		}
+	}
+
+	save()
+
+	return scanner.Err()
+}
+
+// LoadDirectory loads the message catalogs of the provided folder, each file is named after its
+// culture (E.g. locales/fr.json or locales/fr-CA.po)
+func (localizer *Localizer) LoadDirectory(path string) error {
+	if strings.HasPrefix(path, "~/") || strings.HasPrefix(path, "./") {
+		path = GetApplicationPath() + path[1:]
+	}
+
+	localizer.Path = path
+	return walkFiles(path, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(filename))
+		if ext != ".json" && ext != ".po" {
+			return nil
+		}
+
+		data, err := readFile(filename)
+		if err != nil {
+			return err
+		}
+
+		culture := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		if ext == ".po" {
+			err = localizer.LoadPO(culture, data)
+		} else {
+			err = localizer.LoadJSON(culture, data)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: %s", filename, err)
+		}
+
+		return nil
+	})
+}
+
+// Reload clears the message catalogs and loads them again from the Path
+func (localizer *Localizer) Reload() error {
+	localizer.mutex.Lock()
+	localizer.catalogs = make(map[string]map[string]map[string]string, 0)
+	localizer.mutex.Unlock()
+
+	if localizer.Path == "" {
+		return nil
+	}
+
+	return localizer.LoadDirectory(localizer.Path)
+}
+
+// Match returns the supported culture that best matches the provided culture, the exact
+// culture, its language, or another culture of the same language, or an empty string
+func (localizer *Localizer) Match(culture string) string {
+	culture = NormalizeCulture(culture)
+	if culture == "" {
+		return ""
+	}
+
+	language := CultureLanguage(culture)
+	for _, candidate := range []string{culture, language} {
+		for _, supported := range localizer.Cultures {
+			if supported == candidate {
+				return supported
+			}
+		}
+	}
+
+	for _, supported := range localizer.Cultures {
+		if CultureLanguage(supported) == language {
+			return supported
+		}
+	}
+
+	return ""
+}
+
+// ParseAcceptLanguage returns the cultures of the provided Accept-Language header, ordered by
+// their quality value
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		culture string
+		quality float64
+	}
+
+	values := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		culture := strings.TrimSpace(fields[0])
+		if culture == "" || culture == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(field, "q=") {
+				if q, err := strconv.ParseFloat(field[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		if quality > 0 {
+			values = append(values, weighted{culture: culture, quality: quality})
+		}
+	}
+
+	sort.SliceStable(values, func(i int, j int) bool { return values[i].quality > values[j].quality })
+	rtn := make([]string, 0, len(values))
+	for _, value := range values {
+		rtn = append(rtn, NormalizeCulture(value.culture))
+	}
+
+	return rtn
+}
+
+// routeCulture returns the supported culture of the first segment of the provided url path
+// and the path without it, or an empty culture
+func (localizer *Localizer) routeCulture(path string) (string, string) {
+	trimmed := strings.TrimLeft(path, "/")
+	segment, rest := trimmed, ""
+	if i := strings.Index(trimmed, "/"); i >= 0 {
+		segment, rest = trimmed[:i], trimmed[i:]
+	}
+
+	for _, supported := range localizer.Cultures {
+		if strings.EqualFold(supported, NormalizeCulture(segment)) {
+			if rest == "" {
+				rest = "/"
+			}
+
+			return supported, rest
+		}
+	}
+
+	return "", path
+}
+
+// DetectCulture returns the culture of the provided request and where it was found, from the
+// route prefix, the QueryKey query string value, the CookieName cookie, the Accept-Language
+// header, or the default culture
+func (localizer *Localizer) DetectCulture(request *http.Request) (string, string) {
+	if selection, ok := request.Context().Value(cultureContextKey{}).(*cultureSelection); ok {
+		return selection.culture, selection.source
+	}
+
+	if culture, _ := localizer.routeCulture(request.URL.Path); culture != "" {
+		return culture, CultureSourceRoute
+	}
+
+	if localizer.QueryKey != "" {
+		if culture := localizer.Match(request.URL.Query().Get(localizer.QueryKey)); culture != "" {
+			return culture, CultureSourceQuery
+		}
+	}
+
+	if localizer.CookieName != "" {
+		if cookie, err := request.Cookie(localizer.CookieName); err == nil {
+			if culture := localizer.Match(cookie.Value); culture != "" {
+				return culture, CultureSourceCookie
+			}
+		}
+	}
+
+	for _, requested := range ParseAcceptLanguage(request.Header.Get("Accept-Language")) {
+		if culture := localizer.Match(requested); culture != "" {
+			return culture, CultureSourceHeader
+		}
+	}
+
+	return localizer.DefaultCulture(), CultureSourceDefault
+}
+
+// LocalizeRequest detects the culture of the provided request and returns a copy of it holding
+// the culture, with the culture route prefix removed from its url path so that it is routed
+// as usual (E.g. /fr/home/index is routed as /home/index)
+func (localizer *Localizer) LocalizeRequest(request *http.Request) *http.Request {
+	culture, source := localizer.DetectCulture(request)
+	rtn := request.WithContext(context.WithValue(request.Context(), cultureContextKey{}, &cultureSelection{culture: culture, source: source}))
+
+	if source == CultureSourceRoute {
+		_, path := localizer.routeCulture(request.URL.Path)
+		url := *request.URL
+		url.Path = path
+		url.RawPath = ""
+		rtn.URL = &url
+	}
+
+	return rtn
+}
+
+// lookup returns the plural forms of the provided key and the culture they were found in,
+// trying the culture, its language and then the default culture
+func (localizer *Localizer) lookup(culture string, key string) (map[string]string, string) {
+	localizer.mutex.RLock()
+	defer localizer.mutex.RUnlock()
+
+	candidates := []string{culture, CultureLanguage(culture), localizer.DefaultCulture(), CultureLanguage(localizer.DefaultCulture())}
+	for _, candidate := range candidates {
+		if forms, ok := localizer.catalogs[candidate][key]; ok {
+			return forms, candidate
+		}
+	}
+
+	return nil, culture
+}
+
+// Translate returns the message of the provided key in the provided culture, formatted with the
+// provided arguments (see FormatMessage). When the first argument is a number it selects the
+// plural form of the message. The key itself is formatted when no catalog holds the message
+func (localizer *Localizer) Translate(culture string, key string, args ...interface{}) string {
+	if localizer == nil {
+		return FormatMessage(culture, key, args...)
+	}
+
+	culture = NormalizeCulture(culture)
+	if culture == "" {
+		culture = localizer.DefaultCulture()
+	}
+
+	forms, found := localizer.lookup(culture, key)
+	if forms == nil {
+		return FormatMessage(culture, key, args...)
+	}
+
+	message, ok := forms[PluralOther]
+	if len(args) > 0 && len(forms) > 1 {
+		if count, err := toFloat(args[0]); err == nil {
+			if text, exists := forms[PluralCategory(found, count)]; exists {
+				message, ok = text, true
+			}
+		}
+	}
+
+	if !ok {
+		for _, category := range GetPluralRule(found).Categories {
+			if text, exists := forms[category]; exists {
+				message = text
+				break
+			}
+		}
+	}
+
+	return FormatMessage(culture, message, args...)
+}
+
+// FormatMessage replaces the {0}, {1}, ... placeholders of the provided message with the
+// provided arguments, numbers and dates are formatted following the provided culture
+func FormatMessage(culture string, message string, args ...interface{}) string {
+	format := GetCultureFormat(culture)
+	for i, arg := range args {
+		placeholder := fmt.Sprintf("{%d}", i)
+		if !strings.Contains(message, placeholder) {
+			continue
+		}
+
+		value := fmt.Sprintf("%v", arg)
+		switch v := arg.(type) {
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			value, _ = format.FormatNumber(v, 0)
+		case float32, float64:
+			number, _ := toFloat(v)
+			decimals := 0
+			if text := strconv.FormatFloat(number, 'f', -1, 64); strings.Contains(text, ".") {
+				decimals = len(text) - strings.Index(text, ".") - 1
+			}
+
+			value, _ = format.FormatNumber(number, decimals)
+		case time.Time:
+			value = format.FormatDate(v, "date")
+		}
+
+		message = strings.Replace(message, placeholder, value, -1)
+	}
+
+	return message
+}
+
+// localizedTemplateNames returns the localized names of the provided template name for the
+// provided culture, most specific first (E.g. index.fr-CA.htm and index.fr.htm)
+func localizedTemplateNames(culture string, name string) []string {
+	culture = NormalizeCulture(culture)
+	if culture == "" {
+		return []string{}
+	}
+
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	rtn := []string{fmt.Sprintf("%s.%s%s", base, culture, ext)}
+	if language := CultureLanguage(culture); language != culture {
+		rtn = append(rtn, fmt.Sprintf("%s.%s%s", base, language, ext))
+	}
+
+	return rtn
+}
+
+// LocalizeTemplates returns the provided template names with each replaced by its localized
+// name for the provided culture when that view exists (E.g. index.htm is index.fr.htm)
+func (cache *ViewCache) LocalizeTemplates(controllerName string, culture string, templates []string) []string {
+	rtn := make([]string, 0, len(templates))
+	for _, name := range templates {
+		localized := name
+		for _, candidate := range localizedTemplateNames(culture, name) {
+			if len(cache.ResolveTemplates(controllerName, []string{candidate})) > 0 {
+				localized = candidate
+				break
+			}
+		}
+
+		rtn = append(rtn, localized)
+	}
+
+	return rtn
+}
+
+// MakeLocalizedTemplateList returns the full path and filenames of the provided templates (see
+// MakeTemplateList), using the localized view of the provided culture when it exists
+func MakeLocalizedTemplateList(controllerName string, culture string, templates []string) []string {
+	var cache *ViewCache
+	return MakeTemplateList(controllerName, cache.LocalizeTemplates(controllerName, culture, templates))
+}
+
+// T returns the message of the provided key translated to the controllers Culture (see
+// Localizer.Translate), this is the T function available to views
+func (controller *Controller) T(key string, args ...interface{}) string {
+	return controller.Localizer.Translate(controller.Culture, key, args...)
+}
+
+// SetCulture sets the controllers Culture and stores it in the users culture cookie so that it
+// is used by their following requests
+func (controller *Controller) SetCulture(culture string) {
+	controller.Culture = NormalizeCulture(culture)
+	if controller.Localizer == nil || controller.Localizer.CookieName == "" {
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:    controller.Localizer.CookieName,
+		Value:   controller.Culture,
+		Path:    "/",
+		Expires: time.Now().AddDate(1, 0, 0),
+	}
+
+	for i, existing := range controller.Cookies {
+		if existing.Name == cookie.Name {
+			controller.Cookies[i] = cookie
+			return
+		}
+	}
+
+	controller.Cookies = append(controller.Cookies, cookie)
+}
+
+// CultureFormat returns the number and date format of the controllers Culture
+func (controller *Controller) CultureFormat() *CultureFormat {
+	return GetCultureFormat(controller.Culture)
+}
+
+// LocalizeURL returns the provided root relative url with the culture route prefix added when
+// the culture of this request came from the route (E.g. "/home/index" is "/fr/home/index" for
+// a request of /fr/home), so that links and redirects keep the selected culture. Absolute urls
+// and urls that already hold a culture prefix are returned as is
+func (controller *Controller) LocalizeURL(url string) string {
+	if controller.Localizer == nil || controller.Request == nil || controller.Culture == "" {
+		return url
+	}
+
+	if !strings.HasPrefix(url, "/") || strings.HasPrefix(url, "//") {
+		return url
+	}
+
+	if _, source := controller.Localizer.DetectCulture(controller.Request); source != CultureSourceRoute {
+		return url
+	}
+
+	if culture, _ := controller.Localizer.routeCulture(url); culture != "" {
+		return url
+	}
+
+	if url == "/" {
+		return "/" + controller.Culture
+	}
+
+	return "/" + controller.Culture + url
+}
+
+// URL returns the root relative url path of the provided controller, action and parameters
+// (see URL) with the culture route prefix of this request (see LocalizeURL), this is the Url
+// function available to views
+func (controller *Controller) URL(parts ...interface{}) string {
+	return controller.LocalizeURL(URL(parts...))
+}
+
+// localizedViewFuncs returns the culture aware template functions of the controller, replacing
+// the standard formatting functions
+func (controller *Controller) localizedViewFuncs() template.FuncMap {
+	return template.FuncMap{
+		"T":   controller.T,
+		"Url": controller.URL,
+		"FormatNumber": func(value interface{}, decimals int) (string, error) {
+			return controller.CultureFormat().FormatNumber(value, decimals)
+		},
+		"FormatCurrency": func(value interface{}, symbol string) (string, error) {
+			return controller.CultureFormat().FormatCurrency(value, symbol)
+		},
+		"FormatDate": func(value time.Time, layout string) string {
+			return controller.CultureFormat().FormatDate(value, layout)
+		},
+	}
+}
@@ -0,0 +1,287 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Localization Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of localization.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in localization.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestLocalizer_Translate ensures that json and po messages are translated with their plural forms
+func TestLocalizer_Translate(t *testing.T) {
+	localizer := mvcapp.NewLocalizer("en-US", "fr", "ru")
+
+	err := localizer.LoadJSON("en-US", []byte(`{
+		"Welcome": "Welcome {0}",
+		"CartItems": { "one": "{0} item", "other": "{0} items" },
+		"OnlyEnglish": "Only in english"
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = localizer.LoadPO("fr", []byte(`# French messages
+msgid ""
+msgstr ""
+"Language: fr\n"
+
+msgid "Welcome"
+msgstr "Bienvenue "
+"{0}"
+
+#, fuzzy
+msgid "Goodbye"
+msgstr "Au revoir"
+
+msgid "Untranslated"
+msgstr ""
+
+msgid "CartItems"
+msgid_plural "CartItems"
+msgstr[0] "{0} article"
+msgstr[1] "{0} articles"
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = localizer.LoadJSON("ru", []byte(`{ "CartItems": { "one": "{0} товар", "few": "{0} товара", "many": "{0} товаров" } }`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		culture  string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "Welcome", []interface{}{"Dan"}, "Welcome Dan"},
+		{"fr", "Welcome", []interface{}{"Dan"}, "Bienvenue Dan"},
+		{"fr-CA", "Welcome", []interface{}{"Dan"}, "Bienvenue Dan"},
+		{"en-US", "CartItems", []interface{}{1}, "1 item"},
+		{"en-US", "CartItems", []interface{}{1500}, "1,500 items"},
+		{"fr", "CartItems", []interface{}{0}, "0 article"},
+		{"fr", "CartItems", []interface{}{1500}, "1 500 articles"},
+		{"ru", "CartItems", []interface{}{22}, "22 товара"},
+		{"ru", "CartItems", []interface{}{25}, "25 товаров"},
+		{"fr", "OnlyEnglish", nil, "Only in english"},
+		{"fr", "Goodbye", nil, "Goodbye"},
+		{"fr", "Untranslated", nil, "Untranslated"},
+		{"fr", "Missing {0}", []interface{}{2.5}, "Missing 2,5"},
+	}
+
+	for _, c := range cases {
+		if result := localizer.Translate(c.culture, c.key, c.args...); result != c.expected {
+			t.Errorf("Unexpected translation of %s in %s: %s", c.key, c.culture, result)
+		}
+	}
+
+	if err = localizer.LoadPO("fr", []byte("msgid \"Broken\nmsgstr \"\"")); err == nil {
+		t.Error("Failed to report the invalid po file")
+	}
+}
+
+// TestLocalizer_LoadDirectory ensures that the message catalogs of a folder are loaded
+func TestLocalizer_LoadDirectory(t *testing.T) {
+	locales := fmt.Sprintf("%s/locales", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(locales)
+
+	os.MkdirAll(locales, 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/en.json", locales), []byte(`{ "Hello": "Hello" }`), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/fr.po", locales), []byte("msgid \"Hello\"\nmsgstr \"Bonjour\"\n"), 0644)
+
+	localizer := mvcapp.NewLocalizer("en", "fr")
+	if err := localizer.LoadDirectory("./locales"); err != nil {
+		t.Fatal(err)
+	}
+
+	if localizer.Translate("fr", "Hello") != "Bonjour" || localizer.Translate("en", "Hello") != "Hello" {
+		t.Error("Failed to load the message catalogs")
+	}
+
+	ioutil.WriteFile(fmt.Sprintf("%s/fr.po", locales), []byte("msgid \"Hello\"\nmsgstr \"Salut\"\n"), 0644)
+	if err := localizer.Reload(); err != nil || localizer.Translate("fr", "Hello") != "Salut" {
+		t.Error("Failed to reload the message catalogs")
+	}
+}
+
+// TestParseAcceptLanguage ensures that the Accept-Language header is ordered by quality
+func TestParseAcceptLanguage(t *testing.T) {
+	result := mvcapp.ParseAcceptLanguage("de;q=0.5, fr-ca, *;q=0.1, en;q=0.8, es;q=0")
+	if !reflect.DeepEqual(result, []string{"fr-CA", "en", "de"}) {
+		t.Errorf("Unexpected accept language order: %v", result)
+	}
+}
+
+// TestLocalizer_DetectCulture ensures that the culture is detected from the route, query string,
+// cookie and Accept-Language header
+func TestLocalizer_DetectCulture(t *testing.T) {
+	localizer := mvcapp.NewLocalizer("en-US", "fr", "de")
+
+	newRequest := func(url string, cookie string, header string) *http.Request {
+		req := httptest.NewRequest("GET", url, nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: "mvcapp.culture", Value: cookie})
+		}
+
+		if header != "" {
+			req.Header.Set("Accept-Language", header)
+		}
+
+		return req
+	}
+
+	cases := []struct {
+		request *http.Request
+		culture string
+		source  string
+	}{
+		{newRequest("http://localhost/fr/home/index?culture=de", "de", "de"), "fr", mvcapp.CultureSourceRoute},
+		{newRequest("http://localhost/home/index?culture=de", "fr", "fr"), "de", mvcapp.CultureSourceQuery},
+		{newRequest("http://localhost/home/index?culture=xx", "fr", "de"), "fr", mvcapp.CultureSourceCookie},
+		{newRequest("http://localhost/home/index", "", "es, fr-CA;q=0.9"), "fr", mvcapp.CultureSourceHeader},
+		{newRequest("http://localhost/home/index", "", "en-GB"), "en-US", mvcapp.CultureSourceHeader},
+		{newRequest("http://localhost/home/index", "", ""), "en-US", mvcapp.CultureSourceDefault},
+	}
+
+	for _, c := range cases {
+		culture, source := localizer.DetectCulture(c.request)
+		if culture != c.culture || source != c.source {
+			t.Errorf("Unexpected culture %s (%s) for %s", culture, source, c.request.URL)
+		}
+	}
+
+	req := localizer.LocalizeRequest(newRequest("http://localhost/fr/home/index", "", ""))
+	if req.URL.Path != "/home/index" {
+		t.Errorf("Failed to remove the culture route prefix: %s", req.URL.Path)
+	}
+
+	if culture, source := localizer.DetectCulture(req); culture != "fr" || source != mvcapp.CultureSourceRoute {
+		t.Errorf("Failed to keep the detected culture: %s (%s)", culture, source)
+	}
+}
+
+// TestRouteManager_Localizer ensures that requests are routed with their culture prefix and that
+// controllers render localized views and messages
+func TestRouteManager_Localizer(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/test", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/test/index.htm", views), []byte("{{ define \"mvcapp\" }}{{ .Culture }}|{{ T \"Hello\" }}|{{ FormatNumber 1234.5 1 }}{{ end }}"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/test/index.fr.htm", views), []byte("{{ define \"mvcapp\" }}fr: {{ T \"Hello\" }}|{{ FormatNumber 1234.5 1 }}{{ end }}"), 0644)
+
+	manager := mvcapp.NewRouteManager()
+	manager.Localizer = mvcapp.NewLocalizer("en", "fr", "de")
+	manager.Localizer.LoadJSON("en", []byte(`{ "Hello": "Hello" }`))
+	manager.Localizer.LoadJSON("fr", []byte(`{ "Hello": "Bonjour" }`))
+	manager.RegisterController("test", newTestController)
+
+	recorder := httptest.NewRecorder()
+	manager.HandleRequest(recorder, httptest.NewRequest("GET", "http://localhost/fr/test/index", nil))
+	if recorder.Body.String() != "test" {
+		t.Errorf("Failed to route the localized request: %s", recorder.Body.String())
+	}
+
+	req := manager.Localizer.LocalizeRequest(httptest.NewRequest("GET", "http://localhost/fr/test/index", nil))
+	_, controller := manager.GetController(httptest.NewRecorder(), req)
+	if controller == nil || controller.Culture != "fr" || controller.RequestedPath != "test/index" {
+		t.Fatal("Failed to set the controller culture")
+	}
+
+	res := controller.View([]string{"index.htm"}, nil)
+	if string(res.Data) != "fr: Bonjour|1 234,5" {
+		t.Errorf("Unexpected localized view: %s", res.Data)
+	}
+
+	req = manager.Localizer.LocalizeRequest(httptest.NewRequest("GET", "http://localhost/test/index?culture=de", nil))
+	_, controller = manager.GetController(httptest.NewRecorder(), req)
+	res = controller.View([]string{"index.htm"}, nil)
+	if string(res.Data) != "de|Hello|1.234,5" {
+		t.Errorf("Unexpected fallback view: %s", res.Data)
+	}
+
+	found := false
+	for _, cookie := range controller.Cookies {
+		found = found || (cookie.Name == "mvcapp.culture" && cookie.Value == "de")
+	}
+
+	if !found {
+		t.Error("Failed to store the culture selected by the query string")
+	}
+
+	if url := controller.URL("test", "about"); url != "/test/about" {
+		t.Errorf("Unexpected url of a culture selected by the query string: %s", url)
+	}
+
+	files := mvcapp.MakeLocalizedTemplateList("test", "fr-CA", []string{"index.htm"})
+	if len(files) != 1 || files[0] != fmt.Sprintf("%s/test/index.fr.htm", views) {
+		t.Errorf("Unexpected localized template list: %v", files)
+	}
+}
+
+// TestController_LocalizeURL ensures that urls and redirects keep the culture route prefix and
+// are routed back to the same culture
+func TestController_LocalizeURL(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/test", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/test/links.htm", views), []byte("{{ define \"mvcapp\" }}{{ Url \"test\" \"index\" 42 }}{{ end }}"), 0644)
+
+	manager := mvcapp.NewRouteManager()
+	manager.Localizer = mvcapp.NewLocalizer("en", "fr-CA")
+	manager.RegisterController("test", newTestController)
+
+	req := manager.Localizer.LocalizeRequest(httptest.NewRequest("GET", "http://localhost/fr-ca/test/links", nil))
+	_, controller := manager.GetController(httptest.NewRecorder(), req)
+	if controller == nil || controller.Culture != "fr-CA" {
+		t.Fatal("Failed to set the controller culture")
+	}
+
+	if res := controller.View([]string{"links.htm"}, nil); string(res.Data) != "/fr-CA/test/index/42" {
+		t.Errorf("Unexpected localized view url: %s", res.Data)
+	}
+
+	cases := map[string]string{
+		"/":                     "/fr-CA",
+		"/test/about?a=1":       "/fr-CA/test/about?a=1",
+		"/fr-CA/test/about":     "/fr-CA/test/about",
+		"https://example.com/x": "https://example.com/x",
+		"//example.com/x":       "//example.com/x",
+	}
+
+	for url, expected := range cases {
+		if result := controller.LocalizeURL(url); result != expected {
+			t.Errorf("Unexpected localized url of %s: %s", url, result)
+		}
+	}
+
+	res := controller.RedirectToAction("test", "about", "a b")
+	location := res.Headers["Location"]
+	if res.StatusCode != http.StatusFound || location != "/fr-CA/test/about/a%20b" {
+		t.Fatalf("Unexpected localized redirect: %d %s", res.StatusCode, location)
+	}
+
+	// The redirect is routed back to the same culture
+	req = manager.Localizer.LocalizeRequest(httptest.NewRequest("GET", "http://localhost"+location, nil))
+	_, controller = manager.GetController(httptest.NewRecorder(), req)
+	if controller == nil || controller.Culture != "fr-CA" || controller.RequestedPath != "test/about/a b" {
+		t.Error("Failed to route the localized redirect")
+	}
+}
@@ -0,0 +1,189 @@
+/*
+	Digivance MVC Application Framework
+	Markdown Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the built in markdown converter used by the MarkdownViewEngine. It supports
+	the common subset of markdown (headings, paragraphs, emphasis, code spans and fenced code
+	blocks, links, images, lists, block quotes and horizontal rules). Raw html in the markdown is
+	escaped rather than passed through, and link urls are filtered with SafeURL.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Block level markdown patterns
+var (
+	markdownHeading    = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownRule       = regexp.MustCompile(`^\s{0,3}([-*_])(\s*([-*_])){2,}\s*$`)
+	markdownUnordered  = regexp.MustCompile(`^\s{0,3}[-*+]\s+(.*)$`)
+	markdownOrdered    = regexp.MustCompile(`^\s{0,3}\d+[.)]\s+(.*)$`)
+	markdownBlockQuote = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	markdownFence      = regexp.MustCompile("^\\s{0,3}(```|~~~)\\s*([\\w+-]*)")
+)
+
+// Inline markdown patterns, applied to html escaped text
+var (
+	markdownCodeSpan = regexp.MustCompile("`([^`]+)`")
+	markdownImage    = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)(?:\s+&#34;([^&]*)&#34;)?\)`)
+	markdownLink     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)(?:\s+&#34;([^&]*)&#34;)?\)`)
+	markdownStrong   = regexp.MustCompile(`(\*\*|__)(\S(?:.*?\S)?)(\*\*|__)`)
+	markdownEmphasis = regexp.MustCompile(`(^|[^\w*])[*_](\S(?:.*?\S)?)[*_]([^\w*]|$)`)
+)
+
+// MarkdownConverter converts markdown source to html
+type MarkdownConverter func(source []byte) template.HTML
+
+// Markdown converts the provided markdown source to html using the built in converter
+func Markdown(source []byte) template.HTML {
+	lines := strings.Split(strings.Replace(string(source), "\r\n", "\n", -1), "\n")
+	output := new(bytes.Buffer)
+	markdownBlocks(output, lines)
+	return template.HTML(strings.TrimSpace(output.String()))
+}
+
+// markdownBlocks writes the html of the provided markdown lines
+func markdownBlocks(output *bytes.Buffer, lines []string) {
+	paragraph := []string{}
+	flush := func() {
+		if len(paragraph) > 0 {
+			fmt.Fprintf(output, "<p>%s</p>\n", markdownInline(strings.Join(paragraph, "\n")))
+			paragraph = []string{}
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+
+		case markdownFence.MatchString(line):
+			flush()
+			match := markdownFence.FindStringSubmatch(line)
+			code := []string{}
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), match[1]); i++ {
+				code = append(code, lines[i])
+			}
+
+			class := ""
+			if match[2] != "" {
+				class = fmt.Sprintf(" class=\"language-%s\"", template.HTMLEscapeString(match[2]))
+			}
+
+			fmt.Fprintf(output, "<pre><code%s>%s</code></pre>\n", class, template.HTMLEscapeString(strings.Join(code, "\n")))
+
+		case markdownHeading.MatchString(line):
+			flush()
+			match := markdownHeading.FindStringSubmatch(line)
+			fmt.Fprintf(output, "<h%d>%s</h%d>\n", len(match[1]), markdownInline(match[2]), len(match[1]))
+
+		case markdownRule.MatchString(line):
+			flush()
+			output.WriteString("<hr />\n")
+
+		case markdownBlockQuote.MatchString(line):
+			flush()
+			quoted := []string{}
+			for ; i < len(lines) && markdownBlockQuote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, markdownBlockQuote.FindStringSubmatch(lines[i])[1])
+			}
+
+			i--
+			output.WriteString("<blockquote>\n")
+			markdownBlocks(output, quoted)
+			output.WriteString("</blockquote>\n")
+
+		case markdownUnordered.MatchString(line), markdownOrdered.MatchString(line):
+			flush()
+			pattern, tag := markdownUnordered, "ul"
+			if !markdownUnordered.MatchString(line) {
+				pattern, tag = markdownOrdered, "ol"
+			}
+
+			fmt.Fprintf(output, "<%s>\n", tag)
+			for i < len(lines) && pattern.MatchString(lines[i]) {
+				item := []string{pattern.FindStringSubmatch(lines[i])[1]}
+
+				// Indented lines continue the list item
+				for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "" && strings.HasPrefix(lines[i], "  ") && !pattern.MatchString(lines[i]); i++ {
+					item = append(item, strings.TrimSpace(lines[i]))
+				}
+
+				fmt.Fprintf(output, "<li>%s</li>\n", markdownInline(strings.Join(item, "\n")))
+			}
+
+			i--
+			fmt.Fprintf(output, "</%s>\n", tag)
+
+		default:
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+	}
+
+	flush()
+}
+
+// markdownInline returns the html of the provided inline markdown text
+func markdownInline(text string) string {
+	// Code spans are set aside so that their content isn't formatted
+	codes := []string{}
+	text = markdownCodeSpan.ReplaceAllStringFunc(template.HTMLEscapeString(text), func(match string) string {
+		codes = append(codes, fmt.Sprintf("<code>%s</code>", markdownCodeSpan.FindStringSubmatch(match)[1]))
+		return fmt.Sprintf("\x00%d\x00", len(codes)-1)
+	})
+
+	// Rendered image and link tags are set aside so that their attributes aren't formatted
+	tags := []string{}
+	aside := func(tag string) string {
+		tags = append(tags, tag)
+		return fmt.Sprintf("\x01%d\x01", len(tags)-1)
+	}
+
+	text = markdownImage.ReplaceAllStringFunc(text, func(match string) string {
+		parts := markdownImage.FindStringSubmatch(match)
+		return aside(fmt.Sprintf("<img src=\"%s\" alt=\"%s\"%s />", markdownURL(parts[2]), parts[1], markdownTitle(parts[3])))
+	})
+
+	text = markdownLink.ReplaceAllStringFunc(text, func(match string) string {
+		parts := markdownLink.FindStringSubmatch(match)
+		return aside(fmt.Sprintf("<a href=\"%s\"%s>", markdownURL(parts[2]), markdownTitle(parts[3]))) + parts[1] + "</a>"
+	})
+
+	text = markdownStrong.ReplaceAllString(text, "<strong>$2</strong>")
+	text = markdownEmphasis.ReplaceAllString(text, "$1<em>$2</em>$3")
+	text = strings.Replace(text, "  \n", "<br />\n", -1)
+
+	for i, tag := range tags {
+		text = strings.Replace(text, fmt.Sprintf("\x01%d\x01", i), tag, 1)
+	}
+
+	for i, code := range codes {
+		text = strings.Replace(text, fmt.Sprintf("\x00%d\x00", i), code, 1)
+	}
+
+	return text
+}
+
+// markdownURL returns the provided (html escaped) url filtered by SafeURL
+func markdownURL(escaped string) string {
+	return template.HTMLEscapeString(string(SafeURL(html.UnescapeString(escaped))))
+}
+
+// markdownTitle returns the title attribute of the provided (html escaped) title, if any
+func markdownTitle(escaped string) string {
+	if escaped == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(" title=\"%s\"", escaped)
+}
@@ -0,0 +1,46 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Markdown Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of markdown.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in markdown.go
+*/
+
+package mvcapp_test
+
+import (
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestMarkdown ensures that the built in markdown converter produces the expected html
+func TestMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"# Title":                                  "<h1>Title</h1>",
+		"### Sub title ###":                        "<h3>Sub title</h3>",
+		"Hello **bold** and *em* text":             "<p>Hello <strong>bold</strong> and <em>em</em> text</p>",
+		"Use `a <b> **c**` here":                   "<p>Use <code>a &lt;b&gt; **c**</code> here</p>",
+		"[Home](/home \"Go home\")":                "<p><a href=\"/home\" title=\"Go home\">Home</a></p>",
+		"[Bad](javascript:alert(1))":               "<p><a href=\"#\">Bad</a>)</p>",
+		"![Logo](/logo.png)":                       "<p><img src=\"/logo.png\" alt=\"Logo\" /></p>",
+		"- one\n- two":                             "<ul>\n<li>one</li>\n<li>two</li>\n</ul>",
+		"1. one\n2. two":                           "<ol>\n<li>one</li>\n<li>two</li>\n</ol>",
+		"> quoted":                                 "<blockquote>\n<p>quoted</p>\n</blockquote>",
+		"---":                                      "<hr />",
+		"```go\nfmt.Println(\"<hi>\")\n```":        "<pre><code class=\"language-go\">fmt.Println(&#34;&lt;hi&gt;&#34;)</code></pre>",
+		"<script>alert(1)</script>":                "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		"first\nsecond\n\nthird":                   "<p>first\nsecond</p>\n<p>third</p>",
+		"[init](https://x/y.html#object.__init__)": "<p><a href=\"https://x/y.html#object.__init__\">init</a></p>",
+		"[p](/a/_b_/c) and *em*":                   "<p><a href=\"/a/_b_/c\">p</a> and <em>em</em></p>",
+		"![a_b_c](/img/*x*.png)":                   "<p><img src=\"/img/*x*.png\" alt=\"a_b_c\" /></p>",
+		"[**bold** link](/a_b_)":                   "<p><a href=\"/a_b_\"><strong>bold</strong> link</a></p>",
+	}
+
+	for source, expected := range tests {
+		if html := string(mvcapp.Markdown([]byte(source))); html != expected {
+			t.Errorf("Unexpected html for %q: %q", source, html)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+/*
+	Digivance MVC Application Framework
+	Source Map Object
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 3 source map object written next to built content bundles. The
+	source map allows browser developer tools to trace errors in a minified bundle back to the
+	original source files.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// SourceMap is a version 3 source map (https://sourcemaps.info/spec.html)
+type SourceMap struct {
+	// Version is always 3
+	Version int `json:"version"`
+
+	// File is the name of the generated file this source map describes
+	File string `json:"file"`
+
+	// Sources is the list of original source file paths
+	Sources []string `json:"sources"`
+
+	// SourcesContent is the content of each original source file, so browsers don't need to
+	// request the sources separately
+	SourcesContent []string `json:"sourcesContent"`
+
+	// Names is the list of symbol names used by the mappings (unused, always empty)
+	Names []string `json:"names"`
+
+	// Mappings is the base 64 VLQ encoded mapping of generated to original positions
+	Mappings string `json:"mappings"`
+
+	// segments is the list of mapping segments per generated line
+	segments [][]sourceMapSegment
+}
+
+// sourceMapSegment maps a generated column to a position in an original source file
+type sourceMapSegment struct {
+	column       int
+	source       int
+	sourceLine   int
+	sourceColumn int
+}
+
+// NewSourceMap returns a new empty SourceMap for the provided generated file name
+func NewSourceMap(file string) *SourceMap {
+	return &SourceMap{
+		Version:        3,
+		File:           file,
+		Sources:        []string{},
+		SourcesContent: []string{},
+		Names:          []string{},
+		segments:       [][]sourceMapSegment{},
+	}
+}
+
+// AddSource adds an original source file and returns its index for use with AddMapping
+func (sourceMap *SourceMap) AddSource(name string, content string) int {
+	sourceMap.Sources = append(sourceMap.Sources, name)
+	sourceMap.SourcesContent = append(sourceMap.SourcesContent, content)
+	return len(sourceMap.Sources) - 1
+}
+
+// AddMapping maps the zero based generated line and column to the zero based line and column of
+// the provided source index. Mappings must be added in generated order, a mapping of the same
+// generated position as the previous mapping replaces it
+func (sourceMap *SourceMap) AddMapping(line int, column int, source int, sourceLine int, sourceColumn int) {
+	for len(sourceMap.segments) <= line {
+		sourceMap.segments = append(sourceMap.segments, []sourceMapSegment{})
+	}
+
+	segment := sourceMapSegment{
+		column:       column,
+		source:       source,
+		sourceLine:   sourceLine,
+		sourceColumn: sourceColumn,
+	}
+
+	segments := sourceMap.segments[line]
+	if len(segments) > 0 && segments[len(segments)-1].column == column {
+		segments[len(segments)-1] = segment
+		return
+	}
+
+	sourceMap.segments[line] = append(segments, segment)
+}
+
+// sourceToken is an identifier, keyword or literal word of css or javascript content and its
+// zero based position
+type sourceToken struct {
+	text   string
+	line   int
+	column int
+}
+
+// isTokenByte returns true if the provided byte is part of an identifier, keyword or number
+func isTokenByte(b byte) bool {
+	return b == '_' || b == '$' || b >= 0x80 ||
+		(b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// sourceTokens returns the tokens of the provided content, starting at the provided position.
+// Block comments are skipped so their words aren't mistaken for code
+func sourceTokens(data []byte, line int, column int) []sourceToken {
+	rtn := []sourceToken{}
+	for i := 0; i < len(data); {
+		switch {
+		case data[i] == '\n':
+			line, column = line+1, 0
+			i++
+		case data[i] == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return rtn
+			}
+
+			comment := data[i : i+end+4]
+			if n := bytes.Count(comment, []byte("\n")); n > 0 {
+				line, column = line+n, len(comment)-(bytes.LastIndexByte(comment, '\n')+1)
+			} else {
+				column += len(comment)
+			}
+
+			i += len(comment)
+		case isTokenByte(data[i]):
+			start := i
+			for i < len(data) && isTokenByte(data[i]) {
+				i++
+			}
+
+			rtn = append(rtn, sourceToken{text: string(data[start:i]), line: line, column: column})
+			column += i - start
+		default:
+			column++
+			i++
+		}
+	}
+
+	return rtn
+}
+
+// tokenLookahead is the number of original tokens searched for each generated token, so that a
+// token changed by the minifier (E.g. a renamed variable) doesn't skip the mapping far ahead
+const tokenLookahead = 32
+
+// AddTokenMappings maps each token of the generated (E.g. minified) content, which starts at the
+// provided zero based line and column, to the same token in the original content of the provided
+// source index. Generated tokens that can't be found in the original content are left unmapped
+func (sourceMap *SourceMap) AddTokenMappings(generated []byte, line int, column int, source int, original []byte) {
+	originalTokens := sourceTokens(original, 0, 0)
+
+	next := 0
+	for _, token := range sourceTokens(generated, line, column) {
+		for i := next; i < len(originalTokens) && i < next+tokenLookahead; i++ {
+			if originalTokens[i].text == token.text {
+				sourceMap.AddMapping(token.line, token.column, source, originalTokens[i].line, originalTokens[i].column)
+				next = i + 1
+				break
+			}
+		}
+	}
+}
+
+// encodeVLQ appends the base 64 VLQ encoding of value to the provided buffer
+func encodeVLQ(buffer *bytes.Buffer, value int) {
+	const chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+	vlq := value << 1
+	if value < 0 {
+		vlq = (-value << 1) | 1
+	}
+
+	for {
+		digit := vlq & 31
+		vlq >>= 5
+		if vlq > 0 {
+			digit |= 32
+		}
+
+		buffer.WriteByte(chars[digit])
+		if vlq <= 0 {
+			return
+		}
+	}
+}
+
+// encodeMappings returns the encoded Mappings string of the added mapping segments
+func (sourceMap *SourceMap) encodeMappings() string {
+	buffer := new(bytes.Buffer)
+	previous := sourceMapSegment{}
+
+	for line, segments := range sourceMap.segments {
+		if line > 0 {
+			buffer.WriteByte(';')
+		}
+
+		column := 0
+		for i, segment := range segments {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+
+			encodeVLQ(buffer, segment.column-column)
+			encodeVLQ(buffer, segment.source-previous.source)
+			encodeVLQ(buffer, segment.sourceLine-previous.sourceLine)
+			encodeVLQ(buffer, segment.sourceColumn-previous.sourceColumn)
+
+			column = segment.column
+			previous = segment
+		}
+	}
+
+	return buffer.String()
+}
+
+// MarshalJSON encodes the mapping segments and returns the json source map
+func (sourceMap *SourceMap) MarshalJSON() ([]byte, error) {
+	type plainSourceMap SourceMap
+
+	rtn := plainSourceMap(*sourceMap)
+	rtn.Mappings = sourceMap.encodeMappings()
+	return json.Marshal(rtn)
+}
+
+// generatedPosition returns the zero based line and column at the end of the provided data
+func generatedPosition(data []byte) (int, int) {
+	line := bytes.Count(data, []byte("\n"))
+	column := len(data) - (bytes.LastIndexByte(data, '\n') + 1)
+	return line, column
+}
+
+// sourceLines returns the number of lines in the provided source content
+func sourceLines(content string) int {
+	return strings.Count(strings.TrimSuffix(content, "\n"), "\n") + 1
+}
@@ -0,0 +1,72 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Source Map Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of sourcemap.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in sourcemap.go
+*/
+
+package mvcapp_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestSourceMap_MarshalJSON ensures that the SourceMap mappings are encoded as expected
+func TestSourceMap_MarshalJSON(t *testing.T) {
+	sourceMap := mvcapp.NewSourceMap("site.js")
+	a := sourceMap.AddSource("a.js", "var a;\nvar b;\n")
+	b := sourceMap.AddSource("b.js", "var c;\n")
+
+	sourceMap.AddMapping(0, 0, a, 0, 0)
+	sourceMap.AddMapping(1, 0, a, 1, 0)
+	sourceMap.AddMapping(1, 24, b, 0, 0)
+
+	data, err := json.Marshal(sourceMap)
+	if err != nil {
+		t.Fatalf("Failed to marshal source map: %s", err)
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to unmarshal source map: %s", err)
+	}
+
+	if result["version"] != 3.0 || result["file"] != "site.js" || len(result["sources"].([]interface{})) != 2 {
+		t.Errorf("Failed to marshal source map: %s", data)
+	}
+
+	if result["mappings"] != "AAAA;AACA,wBCDA" {
+		t.Errorf("Failed to encode source map mappings: %s", result["mappings"])
+	}
+}
+
+// TestSourceMap_AddTokenMappings ensures that the tokens of minified content are mapped to their
+// original positions
+func TestSourceMap_AddTokenMappings(t *testing.T) {
+	original := "/* color */\nbody {\n  color: red;\n}\n"
+
+	sourceMap := mvcapp.NewSourceMap("site.css")
+	source := sourceMap.AddSource("site.css", original)
+	sourceMap.AddMapping(0, 2, source, 0, 0)
+	sourceMap.AddTokenMappings([]byte("body{color:#f00}"), 0, 2, source, []byte(original))
+
+	data, err := json.Marshal(sourceMap)
+	if err != nil {
+		t.Fatalf("Failed to marshal source map: %s", err)
+	}
+
+	result := map[string]interface{}{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Failed to unmarshal source map: %s", err)
+	}
+
+	// body (0,2 to 1,0) and color (0,7 to 2,2), the rewritten f00 isn't mapped
+	if result["mappings"] != "EACA,KACE" {
+		t.Errorf("Failed to map the minified tokens: %s", result["mappings"])
+	}
+}
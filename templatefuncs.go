@@ -0,0 +1,384 @@
+/*
+	Digivance MVC Application Framework
+	Template Function Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the template function registry shared by views and email templates, along
+	with the standard library of functions it is populated with (formatting, collections, urls,
+	assets and safe output helpers). Register your own functions with TemplateFuncs.Register
+	before the views that use them are first rendered.
+*/
+
+package mvcapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// TemplateFuncRegistry is a thread safe collection of named template functions
+type TemplateFuncRegistry struct {
+	// mutex protects the funcs
+	mutex sync.RWMutex
+
+	// funcs are the registered functions by name
+	funcs template.FuncMap
+}
+
+// TemplateFuncs is the application wide template function registry used by views and emails
+var TemplateFuncs = NewTemplateFuncRegistry()
+
+// NewTemplateFuncRegistry returns a new registry populated with the standard template functions
+func NewTemplateFuncRegistry() *TemplateFuncRegistry {
+	return &TemplateFuncRegistry{
+		funcs: StandardTemplateFuncs(),
+	}
+}
+
+// Register adds (or replaces) the named template function
+func (registry *TemplateFuncRegistry) Register(name string, fn interface{}) error {
+	if name == "" || fn == nil || reflect.TypeOf(fn).Kind() != reflect.Func {
+		return fmt.Errorf("Failed to register template function %s, a name and function are required", name)
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.funcs[name] = fn
+	return nil
+}
+
+// RegisterMap adds (or replaces) each of the provided template functions
+func (registry *TemplateFuncRegistry) RegisterMap(funcs template.FuncMap) error {
+	for name, fn := range funcs {
+		if err := registry.Register(name, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Unregister removes the named template function
+func (registry *TemplateFuncRegistry) Unregister(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	delete(registry.funcs, name)
+}
+
+// FuncMap returns a copy of the registered template functions
+func (registry *TemplateFuncRegistry) FuncMap() template.FuncMap {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	rtn := make(template.FuncMap, len(registry.funcs))
+	for name, fn := range registry.funcs {
+		rtn[name] = fn
+	}
+
+	return rtn
+}
+
+// StandardTemplateFuncs returns the standard library of template functions
+func StandardTemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"ToUpper":        strings.ToUpper,
+		"ToLower":        strings.ToLower,
+		"RawHTML":        RawHTML,
+		"Now":            time.Now,
+		"FormatDate":     FormatDate,
+		"FormatNumber":   FormatNumber,
+		"FormatCurrency": FormatCurrency,
+		"Pluralize":      Pluralize,
+		"Truncate":       Truncate,
+		"Dict":           Dict,
+		"List":           List,
+		"Url":            URL,
+		"Asset":          Asset,
+		"JSON":           JSONValue,
+		"JSONScript":     JSONScript,
+		"Attr":           Attr,
+		"SafeURL":        SafeURL,
+		"QueryEscape":    url.QueryEscape,
+	}
+}
+
+// toFloat converts the provided numeric (or numeric string) value to a float64
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case string:
+		return strconv.ParseFloat(v, 64)
+	case json.Number:
+		return v.Float64()
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+
+	return 0, fmt.Errorf("Failed to convert %v to a number", value)
+}
+
+// FormatDate formats the provided time using the provided layout, which can be a Go time layout
+// or one of the names "date", "time", "datetime", "iso" or "rfc1123"
+func FormatDate(value time.Time, layout string) string {
+	switch strings.ToLower(layout) {
+	case "date":
+		layout = "Jan 2, 2006"
+	case "time":
+		layout = "3:04 PM"
+	case "datetime":
+		layout = "Jan 2, 2006 3:04 PM"
+	case "iso":
+		layout = time.RFC3339
+	case "rfc1123":
+		layout = time.RFC1123
+	}
+
+	return value.Format(layout)
+}
+
+// FormatNumber formats the provided number with the provided number of decimals and comma
+// thousands separators (E.g. 1234.5 with 2 decimals is "1,234.50")
+func FormatNumber(value interface{}, decimals int) (string, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	return formatNumber(number, decimals, ",", "."), nil
+}
+
+// formatNumber formats the provided number with the provided separators
+func formatNumber(number float64, decimals int, thousands string, point string) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	sign := ""
+	if number < 0 {
+		sign = "-"
+		number = math.Abs(number)
+	}
+
+	text := strconv.FormatFloat(number, 'f', decimals, 64)
+	whole, fraction := text, ""
+	if i := strings.Index(text, "."); i >= 0 {
+		whole, fraction = text[:i], text[i+1:]
+	}
+
+	grouped := []string{}
+	for len(whole) > 3 {
+		grouped = append([]string{whole[len(whole)-3:]}, grouped...)
+		whole = whole[:len(whole)-3]
+	}
+
+	grouped = append([]string{whole}, grouped...)
+	rtn := sign + strings.Join(grouped, thousands)
+	if fraction != "" {
+		rtn += point + fraction
+	}
+
+	return rtn
+}
+
+// FormatCurrency formats the provided amount with two decimals, comma thousands separators and
+// the provided currency symbol (E.g. -1234.5 with "$" is "-$1,234.50")
+func FormatCurrency(value interface{}, symbol string) (string, error) {
+	number, err := toFloat(value)
+	if err != nil {
+		return "", err
+	}
+
+	if number < 0 {
+		return "-" + symbol + formatNumber(math.Abs(number), 2, ",", "."), nil
+	}
+
+	return symbol + formatNumber(number, 2, ",", "."), nil
+}
+
+// Pluralize returns the singular word when the count is one, otherwise the plural word
+func Pluralize(count interface{}, singular string, plural string) (string, error) {
+	number, err := toFloat(count)
+	if err != nil {
+		return "", err
+	}
+
+	if number == 1 {
+		return singular, nil
+	}
+
+	return plural, nil
+}
+
+// Truncate shortens the provided text to at most length characters, ending with an ellipsis
+// when the text was shortened
+func Truncate(text string, length int) string {
+	if length <= 0 {
+		return ""
+	}
+
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	runes := []rune(text)
+	if length <= 3 {
+		return string(runes[:length])
+	}
+
+	return strings.TrimSpace(string(runes[:length-3])) + "..."
+}
+
+// Dict returns a map built from the provided key value pairs, useful to pass several values to
+// partial views (E.g. {{ partial "_item" (Dict "Name" .Name "Price" .Price) }})
+func Dict(pairs ...interface{}) (map[string]interface{}, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("Dict requires an even number of key value arguments")
+	}
+
+	rtn := make(map[string]interface{}, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("Dict keys must be strings, received %v", pairs[i])
+		}
+
+		rtn[key] = pairs[i+1]
+	}
+
+	return rtn, nil
+}
+
+// List returns a slice of the provided values
+func List(values ...interface{}) []interface{} {
+	return values
+}
+
+// URL returns the root relative url path of the provided controller, action and parameters,
+// each path escaped (E.g. {{ Url "Products" "Details" .ID }} is "/Products/Details/42")
+func URL(parts ...interface{}) string {
+	segments := []string{}
+	for _, part := range parts {
+		segment := fmt.Sprintf("%v", part)
+		if segment != "" {
+			segments = append(segments, url.PathEscape(segment))
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+// Asset returns the root relative url of the provided static file with a version query string
+// based on its modification time, so browsers fetch the file again when it changes
+func Asset(path string) string {
+	path = "/" + strings.TrimLeft(path, "/")
+	si, err := statFile(GetApplicationPath() + path)
+	if err != nil {
+		return path
+	}
+
+	return fmt.Sprintf("%s?v=%x", path, si.ModTime().Unix())
+}
+
+// JSONValue json encodes the provided value for use inside of a script block
+func JSONValue(value interface{}) (template.JS, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return template.JS(data), nil
+}
+
+// JSONScript returns a json data script tag with the provided id holding the json encoded value,
+// the html special characters are escaped by the encoder so the content can't close the tag
+func JSONScript(id string, value interface{}) (template.HTML, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return template.HTML(fmt.Sprintf("<script type=\"application/json\" id=\"%s\">%s</script>", template.HTMLEscapeString(id), data)), nil
+}
+
+// attrNamePattern matches the html attribute names accepted by Attr
+var attrNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.-]*$`)
+
+// urlAttributes are the html attributes whose values are urls, checked by Attr with SafeURL
+var urlAttributes = map[string]bool{
+	"href":       true,
+	"src":        true,
+	"action":     true,
+	"formaction": true,
+	"xlink:href": true,
+	"poster":     true,
+	"srcset":     true,
+	"data":       true,
+}
+
+// Attr returns an html attribute with the provided name and escaped value. Event handler (on*)
+// and style attributes are refused as their values can't be made safe by escaping, url
+// attributes (E.g. href or src) are refused when the url isn't accepted by SafeURL
+func Attr(name string, value interface{}) (template.HTMLAttr, error) {
+	lower := strings.ToLower(name)
+	if !attrNamePattern.MatchString(name) || strings.HasPrefix(lower, "on") || lower == "style" {
+		return "", fmt.Errorf("Refusing to render unsafe html attribute: %s", name)
+	}
+
+	text := fmt.Sprintf("%v", value)
+	if urlAttributes[lower] {
+		urls := []string{text}
+		if lower == "srcset" {
+			// Each srcset candidate is a url optionally followed by a width or density
+			urls = []string{}
+			for _, candidate := range strings.Split(text, ",") {
+				if fields := strings.Fields(candidate); len(fields) > 0 {
+					urls = append(urls, fields[0])
+				}
+			}
+		}
+
+		for _, u := range urls {
+			if strings.TrimSpace(u) != "#" && SafeURL(u) == "#" {
+				return "", fmt.Errorf("Refusing to render unsafe url in html attribute %s: %s", name, u)
+			}
+		}
+	}
+
+	return template.HTMLAttr(fmt.Sprintf("%s=\"%s\"", name, template.HTMLEscapeString(text))), nil
+}
+
+// SafeURL returns the provided url if it is relative or uses the http, https, mailto or tel
+// schemes, otherwise returns "#" (E.g. javascript: urls)
+func SafeURL(value string) template.URL {
+	parsed, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return "#"
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "", "http", "https", "mailto", "tel":
+		return template.URL(parsed.String())
+	}
+
+	return "#"
+}
@@ -0,0 +1,120 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	Template Function Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of templatefuncs.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in templatefuncs.go
+*/
+
+package mvcapp_test
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestStandardTemplateFuncs ensures that the standard template functions render the expected output
+func TestStandardTemplateFuncs(t *testing.T) {
+	model := map[string]interface{}{
+		"Date":  time.Date(2018, 3, 4, 15, 30, 0, 0, time.UTC),
+		"Total": -1234567.891,
+		"Items": 1,
+		"Text":  "The quick brown fox",
+		"Link":  "javascript:alert(1)",
+		"Data":  map[string]string{"Tag": "</script>"},
+	}
+
+	tests := map[string]string{
+		`{{ FormatDate .Date "date" }}`:                        "Mar 4, 2018",
+		`{{ FormatDate .Date "2006-01-02" }}`:                  "2018-03-04",
+		`{{ FormatNumber .Total 1 }}`:                          "-1,234,567.9",
+		`{{ FormatNumber 999 0 }}`:                             "999",
+		`{{ FormatCurrency .Total "$" }}`:                      "-$1,234,567.89",
+		`{{ .Items }} {{ Pluralize .Items "item" "items" }}`:   "1 item",
+		`{{ Pluralize 3 "item" "items" }}`:                     "items",
+		`{{ Truncate .Text 10 }}`:                              "The qui...",
+		`{{ with Dict "A" 1 "B" 2 }}{{ .A }}{{ .B }}{{ end }}`: "12",
+		`{{ range List "x" "y" }}{{ . }}{{ end }}`:             "xy",
+		`{{ Url "Products" "Details" "a b" }}`:                 "/Products/Details/a%20b",
+		`<a href="{{ SafeURL .Link }}"></a>`:                   `<a href="#"></a>`,
+		`<a {{ Attr "title" "a\"b" }}></a>`:                    `<a title="a&#34;b"></a>`,
+		`{{ JSONScript "data" .Data }}`:                        `<script type="application/json" id="data">{"Tag":"\u003c/script\u003e"}</script>`,
+	}
+
+	for source, expected := range tests {
+		page, err := template.New("test").Funcs(mvcapp.TemplateFuncs.FuncMap()).Parse(source)
+		if err != nil {
+			t.Fatalf("Failed to parse %s: %s", source, err)
+		}
+
+		buffer := new(bytes.Buffer)
+		if err := page.Execute(buffer, model); err != nil {
+			t.Errorf("Failed to execute %s: %s", source, err)
+			continue
+		}
+
+		if buffer.String() != expected {
+			t.Errorf("Unexpected output of %s: %s", source, buffer.String())
+		}
+	}
+
+	if _, err := mvcapp.Attr("onclick", "alert(1)"); err == nil {
+		t.Error("Failed to refuse an event handler attribute")
+	}
+
+	if _, err := mvcapp.Attr("href", " JavaScript:alert(1)"); err == nil {
+		t.Error("Failed to refuse a javascript url attribute")
+	}
+
+	if _, err := mvcapp.Attr("srcset", "/small.png 1x, javascript:alert(1) 2x"); err == nil {
+		t.Error("Failed to refuse a javascript url in a srcset attribute")
+	}
+
+	if attr, err := mvcapp.Attr("href", "/home/index?a=1&b=2"); err != nil || attr != `href="/home/index?a=1&amp;b=2"` {
+		t.Errorf("Unexpected url attribute: %s (%v)", attr, err)
+	}
+}
+
+// TestTemplateFuncRegistry_Register ensures that registered functions are available to views and emails
+func TestTemplateFuncRegistry_Register(t *testing.T) {
+	if err := mvcapp.TemplateFuncs.Register("Shout", "not a function"); err == nil {
+		t.Error("Failed to refuse a non function value")
+	}
+
+	if err := mvcapp.TemplateFuncs.Register("Shout", func(s string) string { return strings.ToUpper(s) + "!" }); err != nil {
+		t.Fatal(err)
+	}
+	defer mvcapp.TemplateFuncs.Unregister("Shout")
+
+	filename := fmt.Sprintf("%s/registry.tpl", mvcapp.GetApplicationPath())
+	defer os.Remove(filename)
+	ioutil.WriteFile(filename, []byte("{{ define \"mvcapp\" }}{{ Shout . }}{{ end }}"), 0644)
+
+	res, err := mvcapp.NewViewResult([]string{filename}, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(res.Data) != "HELLO!" {
+		t.Errorf("Unexpected view result: %s", res.Data)
+	}
+
+	ioutil.WriteFile(filename, []byte("{{ define \"EmailMessage\" }}{{ Shout . }}{{ end }}"), 0644)
+	msg, err := mvcapp.NewEmailMessageFromTemplate("from@domain.tld", "to@domain.tld", "Subject", filename, "hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if msg.Body != "HELLO!" {
+		t.Errorf("Unexpected email body: %s", msg.Body)
+	}
+}
@@ -0,0 +1,237 @@
+/*
+	Digivance MVC Application Framework
+	View Cache Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the compiled view template cache. Template sets are parsed once, keyed by
+	their resolved file list, and cloned for each render so that requests no longer re-read and
+	re-parse the view files from disk. In development mode the cached sets are checked against
+	the modification times of their files (and invalidated by the watcher) so edits show up on
+	the next request.
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// viewCacheEntry is a parsed template set and the modification times of its files
+type viewCacheEntry struct {
+	template *template.Template
+	modTimes map[string]time.Time
+}
+
+// ViewCache parses and caches view template sets and the resolved template file lists
+type ViewCache struct {
+	// Enabled caches the parsed template sets, when false every render parses the view files
+	Enabled bool
+
+	// DevelopmentMode checks the modification times of the cached files on every render and
+	// re-parses the set when they have changed, and doesn't cache the resolved file lists
+	DevelopmentMode bool
+
+	// mutex protects the maps below
+	mutex sync.RWMutex
+
+	// templates are the parsed template sets keyed by their file list and function names
+	templates map[string]*viewCacheEntry
+
+	// resolved are the results of MakeTemplateList keyed by controller and template names
+	resolved map[string][]string
+}
+
+// NewViewCache returns a new, enabled, ViewCache
+func NewViewCache() *ViewCache {
+	return &ViewCache{
+		Enabled:   true,
+		templates: make(map[string]*viewCacheEntry, 0),
+		resolved:  make(map[string][]string, 0),
+	}
+}
+
+// NewViewCacheFromConfig returns a new ViewCache that checks for modified views when the
+// configuration is in DevelopmentMode
+func NewViewCacheFromConfig(config *ConfigurationManager) *ViewCache {
+	rtn := NewViewCache()
+	rtn.DevelopmentMode = config.DevelopmentMode
+	return rtn
+}
+
+// Invalidate removes all of the cached template sets and resolved file lists
+func (cache *ViewCache) Invalidate() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.templates = make(map[string]*viewCacheEntry, 0)
+	cache.resolved = make(map[string][]string, 0)
+}
+
+// Len returns the number of cached template sets
+func (cache *ViewCache) Len() int {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	return len(cache.templates)
+}
+
+// ResolveTemplates returns the MakeTemplateList result for the provided controller and template
+// names, the result is cached unless in DevelopmentMode (where new files may be added). A nil
+// cache resolves without caching
+func (cache *ViewCache) ResolveTemplates(controllerName string, templates []string) []string {
+	if cache == nil || !cache.Enabled || cache.DevelopmentMode {
+		return MakeTemplateList(controllerName, templates)
+	}
+
+	key := controllerName + "\x00" + strings.Join(templates, "\x00")
+	cache.mutex.RLock()
+	rtn, ok := cache.resolved[key]
+	cache.mutex.RUnlock()
+
+	if !ok {
+		rtn = MakeTemplateList(controllerName, templates)
+		cache.mutex.Lock()
+		cache.resolved[key] = rtn
+		cache.mutex.Unlock()
+	}
+
+	return rtn
+}
+
+// viewCacheKey returns the cache key of the provided file list and function names
+func viewCacheKey(files []string, funcMap template.FuncMap) string {
+	names := make([]string, 0, len(funcMap))
+	for name := range funcMap {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return strings.Join(files, "\x00") + "\x01" + strings.Join(names, ",")
+}
+
+// fileModTimes returns the modification times of the provided files
+func fileModTimes(files []string) (map[string]time.Time, error) {
+	rtn := make(map[string]time.Time, len(files))
+	for _, filename := range files {
+		si, err := statFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		rtn[filename] = si.ModTime()
+	}
+
+	return rtn, nil
+}
+
+// modTimesChanged returns true if any of the provided files have been modified or removed
+func modTimesChanged(modTimes map[string]time.Time) bool {
+	for filename, modTime := range modTimes {
+		si, err := statFile(filename)
+		if err != nil || !si.ModTime().Equal(modTime) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Template returns a clone of the parsed template set of the provided files, ready to execute.
+// The set is parsed on first use and cached, the provided functions are bound to the clone so
+// that per request functions (E.g. the controllers Bundle function) can be used. A nil cache
+// parses the files on every call
+func (cache *ViewCache) Template(files []string, funcMap template.FuncMap) (*template.Template, error) {
+	if cache == nil || !cache.Enabled {
+		return parseTemplateFiles(template.New("ViewTemplate").Funcs(funcMap), files...)
+	}
+
+	key := viewCacheKey(files, funcMap)
+	cache.mutex.RLock()
+	entry := cache.templates[key]
+	cache.mutex.RUnlock()
+
+	if entry == nil || (cache.DevelopmentMode && modTimesChanged(entry.modTimes)) {
+		modTimes, err := fileModTimes(files)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := parseTemplateFiles(template.New("ViewTemplate").Funcs(funcMap), files...)
+		if err != nil {
+			return nil, err
+		}
+
+		entry = &viewCacheEntry{template: page, modTimes: modTimes}
+		cache.mutex.Lock()
+		cache.templates[key] = entry
+		cache.mutex.Unlock()
+	}
+
+	page, err := entry.template.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Funcs(funcMap), nil
+}
+
+// NewViewResult returns a new ViewResult (see NewViewResultWithFuncs) rendered from the cached
+// template set of the provided files
+func (cache *ViewCache) NewViewResult(templates []string, model interface{}, funcs template.FuncMap) (*ActionResult, error) {
+	page, err := cache.Template(templates, viewFuncMap(funcs))
+	if err != nil {
+		return nil, err
+	}
+
+	return executeViewTemplate(page, "mvcapp", model)
+}
+
+// Precompile resolves and parses the provided controller templates (see MakeTemplateList),
+// returning an error if any of the templates can't be found or fail to parse. Use this at
+// startup so that missing or broken views fail fast rather than on the first request
+func (cache *ViewCache) Precompile(controllerName string, templates ...string) error {
+	files := cache.ResolveTemplates(controllerName, templates)
+	if len(files) != len(templates) {
+		return fmt.Errorf("Failed to precompile views, only found %d of the templates %v for %s", len(files), templates, controllerName)
+	}
+
+	_, err := cache.Template(files, viewFuncMap(nil))
+	return err
+}
+
+// PrecompileDirectory parses every template file in the provided folder (and its sub folders)
+// individually, returning an error listing each file that fails to parse
+func (cache *ViewCache) PrecompileDirectory(dir string) error {
+	failed := []string{}
+	err := walkFiles(dir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+
+		if _, err := cache.Template([]string{filename}, viewFuncMap(nil)); err != nil {
+			failed = append(failed, err.Error())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("Failed to precompile views: %s", strings.Join(failed, "; "))
+	}
+
+	return nil
+}
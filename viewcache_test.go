@@ -0,0 +1,105 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Cache Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewcache.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewcache.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestViewCache_NewViewResult ensures that parsed templates are cached, that functions are bound
+// per render and that modified views are re-parsed in development mode
+func TestViewCache_NewViewResult(t *testing.T) {
+	pathname := fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views/viewcache")
+	filename := fmt.Sprintf("%s/%s", pathname, "index.htm")
+	defer os.RemoveAll(fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views"))
+
+	os.MkdirAll(pathname, 0755)
+	if err := ioutil.WriteFile(filename, []byte("{{ define \"mvcapp\" }}{{ Greeting }} {{ . }}{{ end }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := mvcapp.NewViewCache()
+	templates := cache.ResolveTemplates("viewcache", []string{"index.htm"})
+	if len(templates) != 1 || templates[0] != filename {
+		t.Fatalf("Failed to resolve the view template: %v", templates)
+	}
+
+	for _, greeting := range []string{"Hello", "Goodbye"} {
+		greeting := greeting
+		funcs := template.FuncMap{"Greeting": func() string { return greeting }}
+		res, err := cache.NewViewResult(templates, "World", funcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(res.Data) != greeting+" World" {
+			t.Errorf("Unexpected view result: %s", res.Data)
+		}
+	}
+
+	if cache.Len() != 1 {
+		t.Fatalf("Expected one cached template set, found %d", cache.Len())
+	}
+
+	funcs := template.FuncMap{"Greeting": func() string { return "Hello" }}
+	ioutil.WriteFile(filename, []byte("{{ define \"mvcapp\" }}Changed{{ end }}"), 0644)
+	modTime := time.Now().Add(time.Minute)
+	os.Chtimes(filename, modTime, modTime)
+
+	res, err := cache.NewViewResult(templates, "World", funcs)
+	if err != nil || string(res.Data) != "Hello World" {
+		t.Fatalf("Expected the cached view outside of development mode, got %s (%v)", res.Data, err)
+	}
+
+	cache.DevelopmentMode = true
+	res, err = cache.NewViewResult(templates, "World", funcs)
+	if err != nil || string(res.Data) != "Changed" {
+		t.Fatalf("Expected the modified view in development mode, got %s (%v)", res.Data, err)
+	}
+
+	cache.Invalidate()
+	if cache.Len() != 0 {
+		t.Error("Failed to invalidate the view cache")
+	}
+}
+
+// TestViewCache_Precompile ensures that missing and broken views are reported by the precompile methods
+func TestViewCache_Precompile(t *testing.T) {
+	pathname := fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views/precompile")
+	defer os.RemoveAll(fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views"))
+
+	os.MkdirAll(pathname, 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/good.htm", pathname), []byte("{{ define \"mvcapp\" }}{{ ToUpper . }}{{ end }}"), 0644)
+
+	cache := mvcapp.NewViewCache()
+	if err := cache.Precompile("precompile", "good.htm"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cache.Precompile("precompile", "good.htm", "missing.htm"); err == nil {
+		t.Error("Failed to report the missing template")
+	}
+
+	if err := cache.PrecompileDirectory(pathname); err != nil {
+		t.Fatal(err)
+	}
+
+	ioutil.WriteFile(fmt.Sprintf("%s/broken.htm", pathname), []byte("{{ define \"mvcapp\" }}{{ UnknownFunc }}{{ end }}"), 0644)
+	if err := cache.PrecompileDirectory(pathname); err == nil {
+		t.Error("Failed to report the broken template")
+	}
+}
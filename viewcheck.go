@@ -0,0 +1,466 @@
+/*
+	Digivance MVC Application Framework
+	View Check Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the view checks used by the mvcapp check command (see cmd/mvcapp). CheckViews
+	parses every template in the views folder with the view function map and reports syntax
+	errors, unknown functions, partial views and view components that can't be found and
+	templates that aren't defined by any view. CheckViewReferences scans Go
+	source files for View, StreamView and SimpleView calls naming templates that MakeTemplateList
+	can't resolve. Both report problems that otherwise only show up as a failed page at runtime.
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template/parse"
+)
+
+// Kinds of view issues
+const (
+	ViewIssueSyntax    = "syntax"
+	ViewIssueFunction  = "function"
+	ViewIssuePartial   = "partial"
+	ViewIssueComponent = "component"
+	ViewIssueTemplate  = "template"
+	ViewIssueView      = "view"
+)
+
+// ViewIssue is a problem found by CheckViews or CheckViewReferences
+type ViewIssue struct {
+	// Filename is the full path and filename of the file with the problem
+	Filename string
+
+	// Line is the line number of the problem (0 when unknown)
+	Line int
+
+	// Kind is the kind of problem (E.g. ViewIssueSyntax)
+	Kind string
+
+	// Message describes the problem
+	Message string
+}
+
+// String returns the issue formatted as filename:line: message
+func (issue ViewIssue) String() string {
+	if issue.Line > 0 {
+		return fmt.Sprintf("%s:%d: %s", issue.Filename, issue.Line, issue.Message)
+	}
+
+	return fmt.Sprintf("%s: %s", issue.Filename, issue.Message)
+}
+
+// viewIssueLine matches the line number of template parse errors and locations
+var viewIssueLine = regexp.MustCompile(`:(\d+):`)
+
+// newViewIssue returns a new ViewIssue, reading the line number from the provided location
+func newViewIssue(filename string, location string, kind string, message string) ViewIssue {
+	line := 0
+	if match := viewIssueLine.FindStringSubmatch(location + ":"); match != nil {
+		line, _ = strconv.Atoi(match[1])
+	}
+
+	return ViewIssue{
+		Filename: filename,
+		Line:     line,
+		Kind:     kind,
+		Message:  message,
+	}
+}
+
+// viewControllers returns the names of the controller folders of the views folder
+func viewControllers() []string {
+	rtn := []string{}
+	root := fmt.Sprintf("%s/views", GetApplicationPath())
+	walkFiles(root, func(filename string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || !info.IsDir() || filename == root {
+			return nil
+		}
+
+		name := filepath.Base(filename)
+		if name != "shared" && name != "components" {
+			rtn = append(rtn, name)
+		}
+
+		return filepath.SkipDir
+	})
+
+	return rtn
+}
+
+// viewResolves returns true if the named template resolves (see MakeTemplateList) for the
+// provided controller, or for any of the controllers when the controller name is empty. Names
+// without a file extension also try ext (see partial views)
+func viewResolves(controllerName string, name string, ext string, controllers []string) bool {
+	candidates := []string{controllerName}
+	if controllerName == "" && len(controllers) > 0 {
+		candidates = controllers
+	}
+
+	for _, candidate := range candidates {
+		if len(MakeTemplateList(candidate, []string{name})) > 0 {
+			return true
+		}
+
+		if filepath.Ext(name) == "" && ext != "" && len(MakeTemplateList(candidate, []string{name + ext})) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// viewControllerName returns the controller name of the provided view file, empty for shared
+// views and views outside of a controller folder
+func viewControllerName(filename string) string {
+	root := filepath.ToSlash(fmt.Sprintf("%s/views/", GetApplicationPath()))
+	parts := strings.Split(strings.TrimPrefix(filepath.ToSlash(filename), root), "/")
+	if len(parts) < 2 || parts[0] == "shared" || parts[0] == "components" {
+		return ""
+	}
+
+	return parts[0]
+}
+
+// walkTemplate calls fn with each node of the provided parse tree
+func walkTemplate(node parse.Node, fn func(node parse.Node)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		fn(n)
+		for _, child := range n.Nodes {
+			walkTemplate(child, fn)
+		}
+	case *parse.ActionNode:
+		fn(n)
+		walkTemplate(n.Pipe, fn)
+	case *parse.IfNode:
+		fn(n)
+		walkTemplate(n.Pipe, fn)
+		walkTemplate(n.List, fn)
+		walkTemplate(n.ElseList, fn)
+	case *parse.RangeNode:
+		fn(n)
+		walkTemplate(n.Pipe, fn)
+		walkTemplate(n.List, fn)
+		walkTemplate(n.ElseList, fn)
+	case *parse.WithNode:
+		fn(n)
+		walkTemplate(n.Pipe, fn)
+		walkTemplate(n.List, fn)
+		walkTemplate(n.ElseList, fn)
+	case *parse.TemplateNode:
+		fn(n)
+		walkTemplate(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		fn(n)
+		for _, cmd := range n.Cmds {
+			walkTemplate(cmd, fn)
+		}
+	case *parse.CommandNode:
+		fn(n)
+		for _, arg := range n.Args {
+			walkTemplate(arg, fn)
+		}
+	}
+}
+
+// templateCalls calls fn with each call of the named function in the provided parse tree that
+// has a string literal first argument
+func templateCalls(node parse.Node, function string, fn func(node parse.Node, name string)) {
+	walkTemplate(node, func(node parse.Node) {
+		if n, ok := node.(*parse.CommandNode); ok && len(n.Args) > 1 {
+			if ident, ok := n.Args[0].(*parse.IdentifierNode); ok && ident.Ident == function {
+				if name, ok := n.Args[1].(*parse.StringNode); ok {
+					fn(n, name.Text)
+				}
+			}
+		}
+	})
+}
+
+// componentExists returns true if the named view component is registered (see ViewComponents)
+// or has a views/components/<name> folder, the registrations of the application aren't known
+// to the mvcapp check command
+func componentExists(name string) bool {
+	if ViewComponents.Component(name) != nil {
+		return true
+	}
+
+	si, err := statFile(fmt.Sprintf("%s/views/components/%s", GetApplicationPath(), strings.ToLower(name)))
+	return err == nil && si.IsDir()
+}
+
+// parsedView is a view file parsed by CheckViews
+type parsedView struct {
+	filename string
+	page     *template.Template
+}
+
+// CheckViews parses every template in the views folder with the view function map, including
+// the provided functions, and returns the syntax errors, unknown functions, missing partial
+// views and view components, and the {{ template }} names that no view defines. Views are
+// combined with their layouts at runtime, so a template defined by any view is accepted.
+// Markdown views aren't templates and are skipped
+func CheckViews(funcs template.FuncMap) ([]ViewIssue, error) {
+	rtn := []ViewIssue{}
+	funcMap := viewFuncMap(funcs)
+	controllers := viewControllers()
+	views := []parsedView{}
+	defined := map[string]bool{}
+
+	root := fmt.Sprintf("%s/views", GetApplicationPath())
+	err := walkFiles(root, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		if _, ok := ViewEngines.Engine(filename).(*MarkdownViewEngine); ok {
+			return nil
+		}
+
+		data, err := readFile(filename)
+		if err != nil {
+			return err
+		}
+
+		page, err := template.New(filepath.Base(filename)).Funcs(funcMap).Parse(string(data))
+		if err != nil {
+			kind := ViewIssueSyntax
+			if strings.Contains(err.Error(), "function") && strings.Contains(err.Error(), "not defined") {
+				kind = ViewIssueFunction
+			}
+
+			rtn = append(rtn, newViewIssue(filename, err.Error(), kind, err.Error()))
+			return nil
+		}
+
+		for _, tmpl := range page.Templates() {
+			defined[tmpl.Name()] = true
+		}
+
+		views = append(views, parsedView{filename: filename, page: page})
+		return nil
+	})
+
+	if err != nil && !os.IsNotExist(err) {
+		return rtn, err
+	}
+
+	for _, view := range views {
+		filename := view.filename
+		controllerName := viewControllerName(filename)
+		for _, tmpl := range view.page.Templates() {
+			if tmpl.Tree == nil {
+				continue
+			}
+
+			tree := tmpl.Tree
+			templateCalls(tree.Root, "partial", func(node parse.Node, name string) {
+				if !viewResolves(controllerName, name, filepath.Ext(filename), controllers) {
+					location, _ := tree.ErrorContext(node)
+					rtn = append(rtn, newViewIssue(filename, location, ViewIssuePartial, fmt.Sprintf("Failed to find partial view: %s", name)))
+				}
+			})
+
+			templateCalls(tree.Root, "component", func(node parse.Node, name string) {
+				if !componentExists(name) {
+					location, _ := tree.ErrorContext(node)
+					rtn = append(rtn, newViewIssue(filename, location, ViewIssueComponent, fmt.Sprintf("Failed to find view component: %s", name)))
+				}
+			})
+
+			walkTemplate(tree.Root, func(node parse.Node) {
+				if n, ok := node.(*parse.TemplateNode); ok && !defined[n.Name] {
+					location, _ := tree.ErrorContext(node)
+					rtn = append(rtn, newViewIssue(filename, location, ViewIssueTemplate, fmt.Sprintf("Template is not defined: %s", n.Name)))
+				}
+			})
+		}
+	}
+
+	sort.SliceStable(rtn, func(i int, j int) bool {
+		if rtn[i].Filename != rtn[j].Filename {
+			return rtn[i].Filename < rtn[j].Filename
+		}
+
+		return rtn[i].Line < rtn[j].Line
+	})
+
+	return rtn, nil
+}
+
+// sourceControllerName returns the controller name of the provided method receiver, the type
+// name without its Controller suffix (E.g. *HomeController is "home")
+func sourceControllerName(decl *ast.FuncDecl) string {
+	if decl.Recv == nil || len(decl.Recv.List) <= 0 {
+		return ""
+	}
+
+	expr := decl.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return ""
+	}
+
+	name := strings.ToLower(ident.Name)
+	return strings.TrimSuffix(name, "controller")
+}
+
+// stringLiterals returns the values of the provided expressions, false if any of them isn't a
+// string literal
+func stringLiterals(exprs []ast.Expr) ([]string, bool) {
+	rtn := []string{}
+	for _, expr := range exprs {
+		literal, ok := expr.(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return nil, false
+		}
+
+		value, err := strconv.Unquote(literal.Value)
+		if err != nil {
+			return nil, false
+		}
+
+		rtn = append(rtn, value)
+	}
+
+	return rtn, true
+}
+
+// viewCallTemplates returns the template names of the provided View, StreamView or SimpleView
+// call, false if it isn't one or its template names aren't string literals
+func viewCallTemplates(call *ast.CallExpr) ([]string, bool) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) <= 0 {
+		return nil, false
+	}
+
+	switch selector.Sel.Name {
+	case "SimpleView":
+		return stringLiterals(call.Args)
+	case "View", "StreamView":
+		literal, ok := call.Args[0].(*ast.CompositeLit)
+		if !ok {
+			return nil, false
+		}
+
+		if array, ok := literal.Type.(*ast.ArrayType); !ok || fmt.Sprintf("%v", array.Elt) != "string" {
+			return nil, false
+		}
+
+		return stringLiterals(literal.Elts)
+	}
+
+	return nil, false
+}
+
+// CheckViewReferences scans the Go source files of the provided folder, and its sub folders,
+// for View, StreamView and SimpleView calls and returns the template names that don't resolve
+// (see MakeTemplateList). The controller name is read from the method receiver (E.g. the
+// "home" views folder for a *HomeController), test files and hidden and vendor folders are
+// skipped, as are calls whose template names aren't string literals
+func CheckViewReferences(sourcePath string) ([]ViewIssue, error) {
+	rtn := []ViewIssue{}
+	controllers := viewControllers()
+	fileSet := token.NewFileSet()
+
+	err := filepath.Walk(sourcePath, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name := info.Name()
+		if info.IsDir() {
+			if filename != sourcePath && (strings.HasPrefix(name, ".") || name == "vendor" || name == "testdata") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fileSet, filename, nil, 0)
+		if err != nil {
+			rtn = append(rtn, ViewIssue{Filename: filename, Kind: ViewIssueSyntax, Message: err.Error()})
+			return nil
+		}
+
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+
+			controllerName := sourceControllerName(funcDecl)
+			known := false
+			for _, controller := range controllers {
+				known = known || controller == controllerName
+			}
+
+			if !known {
+				controllerName = ""
+			}
+
+			ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				templates, ok := viewCallTemplates(call)
+				if !ok {
+					return true
+				}
+
+				for _, view := range templates {
+					if !viewResolves(controllerName, view, "", controllers) {
+						position := fileSet.Position(call.Pos())
+						rtn = append(rtn, ViewIssue{
+							Filename: filename,
+							Line:     position.Line,
+							Kind:     ViewIssueView,
+							Message:  fmt.Sprintf("Failed to find view: %s", view),
+						})
+					}
+				}
+
+				return true
+			})
+		}
+
+		return nil
+	})
+
+	return rtn, err
+}
@@ -0,0 +1,133 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Check Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewcheck.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewcheck.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestCheckViews ensures that syntax errors, unknown functions, missing partials and components and
+// undefined templates are reported
+func TestCheckViews(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/checktest", views), 0755)
+	os.MkdirAll(fmt.Sprintf("%s/shared", views), 0755)
+	os.MkdirAll(fmt.Sprintf("%s/components/menu", views), 0755)
+
+	files := map[string]string{
+		"shared/_layout.htm":    "<html>{{ body }}{{ partial \"_menu\" . }}</html>{{ define \"nav\" }}<nav></nav>{{ end }}",
+		"checktest/widgets.htm": "{{ component \"Menu\" }}\n{{ component \"Missing\" .Model }}",
+		"checktest/named.htm":   "{{ template \"nav\" . }}\n{{ if .Model }}{{ template \"undefined\" }}{{ end }}",
+		"checktest/_menu.htm":   "<ul></ul>",
+		"checktest/index.htm":   "{{ partial \"_menu\" . }}{{ ToUpper .Model }}{{ SiteName }}",
+		"checktest/broken.htm":  "{{ if .Model }}\n<p>{{ .Model }}</p>",
+		"checktest/about.htm":   "<p>About</p>\n{{ with .Model }}{{ partial \"_missing\" . }}{{ end }}",
+		"checktest/typo.htm":    "{{ Bundel \"site.css\" }}",
+		"checktest/notes.md":    "# {{ Not a template",
+	}
+
+	for name, data := range files {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", views, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err := mvcapp.CheckViews(template.FuncMap{"SiteName": func() string { return "" }})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kinds := map[string]mvcapp.ViewIssue{}
+	for _, issue := range issues {
+		name := issue.Filename[len(views)+1:]
+		if _, ok := kinds[name]; ok {
+			t.Errorf("Unexpected second issue: %s", issue)
+		}
+
+		kinds[name] = issue
+	}
+
+	if len(kinds) != 5 {
+		t.Errorf("Unexpected view issues: %v", issues)
+	}
+
+	if issue := kinds["checktest/broken.htm"]; issue.Kind != mvcapp.ViewIssueSyntax {
+		t.Errorf("Failed to report the syntax error: %v", issue)
+	}
+
+	if issue := kinds["checktest/typo.htm"]; issue.Kind != mvcapp.ViewIssueFunction || issue.Line != 1 {
+		t.Errorf("Failed to report the unknown function: %v", issue)
+	}
+
+	if issue := kinds["checktest/about.htm"]; issue.Kind != mvcapp.ViewIssuePartial || issue.Line != 2 || !strings.Contains(issue.String(), "_missing") {
+		t.Errorf("Failed to report the missing partial: %v", issue)
+	}
+
+	if issue := kinds["checktest/widgets.htm"]; issue.Kind != mvcapp.ViewIssueComponent || issue.Line != 2 || !strings.Contains(issue.Message, "Missing") {
+		t.Errorf("Failed to report the missing view component: %v", issue)
+	}
+
+	if issue := kinds["checktest/named.htm"]; issue.Kind != mvcapp.ViewIssueTemplate || issue.Line != 2 || !strings.Contains(issue.Message, "undefined") {
+		t.Errorf("Failed to report the undefined template: %v", issue)
+	}
+}
+
+// TestCheckViewReferences ensures that View and SimpleView calls naming missing templates are
+// reported
+func TestCheckViewReferences(t *testing.T) {
+	root := mvcapp.GetApplicationPath()
+	views := fmt.Sprintf("%s/views", root)
+	source := fmt.Sprintf("%s/checksource", root)
+	defer os.RemoveAll(views)
+	defer os.RemoveAll(source)
+
+	os.MkdirAll(fmt.Sprintf("%s/home", views), 0755)
+	os.MkdirAll(source, 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/home/index.htm", views), []byte("<p>Home</p>"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/home.go", source), []byte(`package site
+
+import "github.com/digivance/mvcapp"
+
+type HomeController struct {
+	*mvcapp.Controller
+}
+
+func (home *HomeController) Index(params []string) *mvcapp.ActionResult {
+	return home.View([]string{"index.htm"}, nil)
+}
+
+func (home *HomeController) About(params []string) *mvcapp.ActionResult {
+	return home.SimpleView("about.htm")
+}
+
+func (home *HomeController) Dynamic(params []string) *mvcapp.ActionResult {
+	name := params[0]
+	return home.View([]string{name}, nil)
+}
+`), 0644)
+
+	issues, err := mvcapp.CheckViewReferences(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(issues) != 1 || issues[0].Kind != mvcapp.ViewIssueView || issues[0].Line != 14 || !strings.Contains(issues[0].Message, "about.htm") {
+		t.Errorf("Unexpected view reference issues: %v", issues)
+	}
+}
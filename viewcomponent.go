@@ -0,0 +1,162 @@
+/*
+	Digivance MVC Application Framework
+	View Component Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines view components, reusable widgets (E.g. menus, sidebars and shopping carts)
+	that load their own data rather than relying on every action to fill the ViewData. A component
+	is registered by name with ViewComponents.Register and invoked from a view with
+	{{ component "ShoppingCart" .User }}. Its Invoke method returns the model and the name of the
+	view to render, which is found in views/components/<name>/ (or the controller and shared
+	folders, see MakeTemplateList), the default view being "default".
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ViewComponentDefaultView is the name of the view rendered by components that don't name one
+const ViewComponentDefaultView = "default"
+
+// ViewComponentResult is the view and model rendered by a view component
+type ViewComponentResult struct {
+	// View is the name of the view found in the components folder, the .htm and .html file
+	// extensions are tried when it doesn't have one
+	View string
+
+	// Model is the data model passed to the view
+	Model interface{}
+}
+
+// NewViewComponentResult returns a new ViewComponentResult rendering the default view with the
+// provided model
+func NewViewComponentResult(model interface{}) *ViewComponentResult {
+	return &ViewComponentResult{
+		View:  ViewComponentDefaultView,
+		Model: model,
+	}
+}
+
+// ViewComponent is a reusable widget that loads its own data. Components are shared by all of
+// the requests and must be safe for concurrent use, per request values are read from the
+// provided controller (E.g. its Session or User)
+type ViewComponent interface {
+	// Invoke returns the view and model to render for the provided arguments
+	Invoke(controller *Controller, args ...interface{}) (*ViewComponentResult, error)
+}
+
+// ViewComponentFunc is a function that can be registered as a view component
+type ViewComponentFunc func(controller *Controller, args ...interface{}) (*ViewComponentResult, error)
+
+// Invoke calls the function
+func (fn ViewComponentFunc) Invoke(controller *Controller, args ...interface{}) (*ViewComponentResult, error) {
+	return fn(controller, args...)
+}
+
+// ViewComponentRegistry is a thread safe collection of view components by name
+type ViewComponentRegistry struct {
+	// mutex protects the components
+	mutex sync.RWMutex
+
+	// components are the registered view components by lower case name
+	components map[string]ViewComponent
+}
+
+// ViewComponents is the application wide view component registry used by the component function
+var ViewComponents = NewViewComponentRegistry()
+
+// NewViewComponentRegistry returns a new, empty, view component registry
+func NewViewComponentRegistry() *ViewComponentRegistry {
+	return &ViewComponentRegistry{
+		components: make(map[string]ViewComponent, 0),
+	}
+}
+
+// Register adds (or replaces) the named view component, names are not case sensitive
+func (registry *ViewComponentRegistry) Register(name string, component ViewComponent) error {
+	if name == "" || component == nil {
+		return fmt.Errorf("Failed to register view component %s, a name and component are required", name)
+	}
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.components[strings.ToLower(name)] = component
+	return nil
+}
+
+// Unregister removes the named view component
+func (registry *ViewComponentRegistry) Unregister(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	delete(registry.components, strings.ToLower(name))
+}
+
+// Component returns the named view component, or nil if it isn't registered
+func (registry *ViewComponentRegistry) Component(name string) ViewComponent {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	return registry.components[strings.ToLower(name)]
+}
+
+// componentFiles resolves the named view of the named component (see MakeTemplateList)
+func (cache *ViewCache) componentFiles(controllerName string, name string, view string) []string {
+	if view == "" {
+		view = ViewComponentDefaultView
+	}
+
+	folder := fmt.Sprintf("components/%s/", strings.ToLower(name))
+	if filepath.Ext(view) != "" {
+		return cache.ResolveTemplates(controllerName, []string{folder + view})
+	}
+
+	for _, ext := range []string{".htm", ".html"} {
+		if files := cache.ResolveTemplates(controllerName, []string{folder + view + ext}); len(files) > 0 {
+			return files
+		}
+	}
+
+	return []string{}
+}
+
+// Component invokes the named view component (see ViewComponents) with the provided arguments
+// and renders its view, this is the component function available to views
+func (controller *Controller) Component(name string, args ...interface{}) (template.HTML, error) {
+	component := ViewComponents.Component(name)
+	if component == nil {
+		return "", fmt.Errorf("Failed to find view component: %s", name)
+	}
+
+	result, err := component.Invoke(controller, args...)
+	if err != nil {
+		return "", err
+	}
+
+	if result == nil {
+		return "", nil
+	}
+
+	controllerName := strings.ToLower(controller.ControllerName)
+	cache := controller.ViewCache
+	files := cache.componentFiles(controllerName, name, result.View)
+	if len(files) <= 0 {
+		return "", fmt.Errorf("Failed to find view %s of view component %s", result.View, name)
+	}
+
+	funcMap := viewFuncMap(controller.ViewFuncs())
+	page, err := cache.Template(files, funcMap)
+	if err != nil {
+		return "", err
+	}
+
+	page = cache.bindLayoutFuncs(page, controllerName, nil, filepath.Ext(files[0]), result.Model, funcMap)
+	return executeTemplateHTML(page, filepath.Base(files[0]), result.Model)
+}
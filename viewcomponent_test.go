@@ -0,0 +1,93 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Component Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewcomponent.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewcomponent.go
+*/
+
+package mvcapp_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// shoppingCart is a test view component that counts the items in the cart of a user
+type shoppingCart struct {
+	items map[string][]string
+}
+
+// Invoke returns the cart of the provided user
+func (cart *shoppingCart) Invoke(controller *mvcapp.Controller, args ...interface{}) (*mvcapp.ViewComponentResult, error) {
+	if len(args) != 1 {
+		return nil, errors.New("The shopping cart requires a user id")
+	}
+
+	items := cart.items[fmt.Sprintf("%v", args[0])]
+	if len(items) <= 0 {
+		return &mvcapp.ViewComponentResult{View: "empty.htm"}, nil
+	}
+
+	return mvcapp.NewViewComponentResult(items), nil
+}
+
+// TestController_Component ensures that view components are invoked and render their views
+func TestController_Component(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/componenttest", views), 0755)
+	os.MkdirAll(fmt.Sprintf("%s/components/shoppingcart", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/componenttest/index.htm", views), []byte("{{ define \"mvcapp\" }}<div>{{ component \"ShoppingCart\" .Model }}</div>{{ end }}"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/componenttest/missing.htm", views), []byte("{{ define \"mvcapp\" }}{{ component \"Missing\" }}{{ end }}"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/components/shoppingcart/default.htm", views), []byte("{{ len . }} items{{ range . }} {{ . }}{{ end }}"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/components/shoppingcart/empty.htm", views), []byte("Empty"), 0644)
+
+	mvcapp.ViewComponents.Register("ShoppingCart", &shoppingCart{
+		items: map[string][]string{"42": {"Apple", "Pear"}},
+	})
+	defer mvcapp.ViewComponents.Unregister("ShoppingCart")
+
+	req, err := http.NewRequest("GET", "http://localhost/componenttest/index", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := newTestController(req).ToController()
+	controller.ControllerName = "componenttest"
+
+	res := controller.View([]string{"index.htm"}, 42)
+	if string(res.Data) != "<div>2 items Apple Pear</div>" {
+		t.Errorf("Unexpected view component output: %s", res.Data)
+	}
+
+	res = controller.View([]string{"index.htm"}, 7)
+	if string(res.Data) != "<div>Empty</div>" {
+		t.Errorf("Failed to render the named view of the view component: %s", res.Data)
+	}
+
+	if _, err = controller.Component("Missing"); err == nil {
+		t.Error("Failed to report the unregistered view component")
+	}
+
+	if _, err = controller.Component("ShoppingCart"); err == nil {
+		t.Error("Failed to report the view component error")
+	}
+
+	mvcapp.ViewComponents.Register("Menu", mvcapp.ViewComponentFunc(func(controller *mvcapp.Controller, args ...interface{}) (*mvcapp.ViewComponentResult, error) {
+		return mvcapp.NewViewComponentResult(nil), nil
+	}))
+	defer mvcapp.ViewComponents.Unregister("Menu")
+
+	if _, err = controller.Component("Menu"); err == nil {
+		t.Error("Failed to report the missing view component view")
+	}
+}
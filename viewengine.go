@@ -0,0 +1,326 @@
+/*
+	Digivance MVC Application Framework
+	View Engine Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the view engine interface and the built in engines. Controller.View selects
+	the engine registered for the file extension of the first template, html/template being the
+	default, text/template is used for plain text views and markdown files are converted to html
+	and rendered into the controllers layout. Register your own engines with ViewEngines.Register
+*/
+
+package mvcapp
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// ViewContext holds the values used to render a view
+type ViewContext struct {
+	// ControllerName is the lower case name of the controller rendering the view, used to
+	// resolve the templates (see MakeTemplateList)
+	ControllerName string
+
+	// Layout is the name of the layout template that wraps the view (empty for no layout)
+	Layout string
+
+	// Templates are the template names requested by the action
+	Templates []string
+
+	// Model is the data model passed to the view
+	Model interface{}
+
+	// Funcs are the per request template functions (E.g. the controllers Bundle function)
+	Funcs template.FuncMap
+
+	// Cache is the controllers view cache (may be nil)
+	Cache *ViewCache
+}
+
+// ViewEngine renders views into action results
+type ViewEngine interface {
+	// Render renders the view described by the provided context
+	Render(context *ViewContext) (*ActionResult, error)
+}
+
+// ViewEngineRegistry is a thread safe collection of view engines by file extension
+type ViewEngineRegistry struct {
+	// Default is the engine used for extensions without a registered engine
+	Default ViewEngine
+
+	// mutex protects the engines
+	mutex sync.RWMutex
+
+	// engines are the registered view engines by lower case file extension
+	engines map[string]ViewEngine
+}
+
+// ViewEngines is the application wide view engine registry used by Controller.View
+var ViewEngines = NewViewEngineRegistry()
+
+// NewViewEngineRegistry returns a new registry with the built in view engines registered
+func NewViewEngineRegistry() *ViewEngineRegistry {
+	rtn := &ViewEngineRegistry{
+		Default: &HTMLViewEngine{},
+		engines: make(map[string]ViewEngine, 0),
+	}
+
+	rtn.Register(NewTextViewEngine(), ".txt", ".text")
+	rtn.Register(NewMarkdownViewEngine(), ".md", ".markdown")
+	return rtn
+}
+
+// Register registers the view engine used to render views with the provided file extensions
+// (E.g. ".md"), replacing any engine previously registered for the extensions
+func (registry *ViewEngineRegistry) Register(engine ViewEngine, extensions ...string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, extension := range extensions {
+		registry.engines[strings.ToLower(extension)] = engine
+	}
+}
+
+// Unregister removes the view engines registered for the provided file extensions
+func (registry *ViewEngineRegistry) Unregister(extensions ...string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	for _, extension := range extensions {
+		delete(registry.engines, strings.ToLower(extension))
+	}
+}
+
+// Engine returns the view engine registered for the extension of the provided template name,
+// or the Default engine
+func (registry *ViewEngineRegistry) Engine(templateName string) ViewEngine {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	if engine := registry.engines[strings.ToLower(filepath.Ext(templateName))]; engine != nil {
+		return engine
+	}
+
+	return registry.Default
+}
+
+// HTMLViewEngine renders html/template views, with layouts, sections and partials (see
+// ViewCache.RenderView)
+type HTMLViewEngine struct{}
+
+// Render renders the view described by the provided context
+func (engine *HTMLViewEngine) Render(context *ViewContext) (*ActionResult, error) {
+	return context.Cache.RenderView(context.ControllerName, context.Layout, context.Templates, context.Model, context.Funcs)
+}
+
+// textViewEntry is a parsed text template set and the modification times of its files
+type textViewEntry struct {
+	template *texttemplate.Template
+	modTimes map[string]time.Time
+}
+
+// TextViewEngine renders text/template views as plain text, the mvcapp template is rendered
+// when defined, otherwise the first template file. Layouts are not used
+type TextViewEngine struct {
+	// mutex protects the templates
+	mutex sync.RWMutex
+
+	// templates are the parsed template sets keyed by their file list
+	templates map[string]*textViewEntry
+}
+
+// NewTextViewEngine returns a new TextViewEngine
+func NewTextViewEngine() *TextViewEngine {
+	return &TextViewEngine{
+		templates: make(map[string]*textViewEntry, 0),
+	}
+}
+
+// template returns the parsed template set of the provided files, cached following the
+// settings of the provided view cache
+func (engine *TextViewEngine) template(cache *ViewCache, files []string, funcMap template.FuncMap) (*texttemplate.Template, error) {
+	parse := func() (*texttemplate.Template, error) {
+		return parseTextTemplateFiles(texttemplate.New("ViewTemplate").Funcs(texttemplate.FuncMap(funcMap)), files...)
+	}
+
+	if cache == nil || !cache.Enabled {
+		return parse()
+	}
+
+	key := viewCacheKey(files, funcMap)
+	engine.mutex.RLock()
+	entry := engine.templates[key]
+	engine.mutex.RUnlock()
+
+	if entry == nil || (cache.DevelopmentMode && modTimesChanged(entry.modTimes)) {
+		modTimes, err := fileModTimes(files)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := parse()
+		if err != nil {
+			return nil, err
+		}
+
+		entry = &textViewEntry{template: page, modTimes: modTimes}
+		engine.mutex.Lock()
+		engine.templates[key] = entry
+		engine.mutex.Unlock()
+	}
+
+	page, err := entry.template.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	return page.Funcs(texttemplate.FuncMap(funcMap)), nil
+}
+
+// Render renders the view described by the provided context
+func (engine *TextViewEngine) Render(context *ViewContext) (*ActionResult, error) {
+	files := context.Cache.ResolveTemplates(context.ControllerName, context.Templates)
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("Failed to find views: %v", context.Templates)
+	}
+
+	page, err := engine.template(context.Cache, files, viewFuncMap(context.Funcs))
+	if err != nil {
+		return nil, err
+	}
+
+	entry := "mvcapp"
+	if page.Lookup(entry) == nil {
+		entry = filepath.Base(files[0])
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := page.ExecuteTemplate(buffer, entry, context.Model); err != nil {
+		return nil, err
+	}
+
+	res := NewActionResult(buffer.Bytes())
+	res.Headers["Content-Type"] = "text/plain; charset=utf-8"
+	return res, nil
+}
+
+// MarkdownViewEngine renders markdown views as html into the controllers layout. The values
+// of the optional front matter (key: value lines between --- lines at the top of the file)
+// and Content (the html) are added to the ViewData of the layouts ViewModel (or are the
+// layouts model, along with Model, when not rendered by Controller.View). A "layout" front
+// matter value overrides the controllers layout
+type MarkdownViewEngine struct {
+	// Converter converts the markdown to html, defaults to the built in Markdown converter
+	Converter MarkdownConverter
+}
+
+// NewMarkdownViewEngine returns a new MarkdownViewEngine using the built in converter
+func NewMarkdownViewEngine() *MarkdownViewEngine {
+	return &MarkdownViewEngine{
+		Converter: Markdown,
+	}
+}
+
+// ParseFrontMatter splits the provided source into its front matter values and content
+func ParseFrontMatter(source []byte) (map[string]interface{}, []byte) {
+	rtn := make(map[string]interface{}, 0)
+	text := strings.Replace(string(source), "\r\n", "\n", -1)
+	if !strings.HasPrefix(text, "---\n") {
+		return rtn, source
+	}
+
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return rtn, source
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text[4 : 4+end]))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+
+		rtn[strings.TrimSpace(parts[0])] = value
+	}
+
+	content := text[4+end+4:]
+	if i := strings.Index(content, "\n"); i >= 0 {
+		content = content[i+1:]
+	} else {
+		content = ""
+	}
+
+	return rtn, []byte(content)
+}
+
+// Render renders the view described by the provided context
+func (engine *MarkdownViewEngine) Render(context *ViewContext) (*ActionResult, error) {
+	files := context.Cache.ResolveTemplates(context.ControllerName, context.Templates)
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("Failed to find views: %v", context.Templates)
+	}
+
+	source, err := readFile(files[0])
+	if err != nil {
+		return nil, err
+	}
+
+	converter := engine.Converter
+	if converter == nil {
+		converter = Markdown
+	}
+
+	values, markdown := ParseFrontMatter(source)
+	content := converter(markdown)
+
+	layout := context.Layout
+	if value, ok := values["layout"].(string); ok {
+		layout = value
+	}
+
+	// The front matter values and content are added to the ViewData of a copy of the envelope
+	var model interface{}
+	if viewModel, ok := context.Model.(*ViewModel); ok {
+		envelope := *viewModel
+		envelope.ViewData = make(map[string]interface{}, len(viewModel.ViewData)+len(values)+1)
+		for key, value := range viewModel.ViewData {
+			envelope.ViewData[key] = value
+		}
+
+		for key, value := range values {
+			envelope.ViewData[key] = value
+		}
+
+		envelope.ViewData["Content"] = content
+		model = &envelope
+	} else {
+		values["Content"] = content
+		values["Model"] = context.Model
+		model = values
+	}
+
+	if layout == "" {
+		return NewActionResult([]byte(content)), nil
+	}
+
+	return context.Cache.RenderLayout(context.ControllerName, layout, content, model, context.Funcs)
+}
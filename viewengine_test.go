@@ -0,0 +1,92 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Engine Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewengine.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewengine.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// upperViewEngine is an emulated third party view engine
+type upperViewEngine struct{}
+
+// Render renders the view described by the provided context
+func (engine *upperViewEngine) Render(context *mvcapp.ViewContext) (*mvcapp.ActionResult, error) {
+	return mvcapp.NewActionResult([]byte(fmt.Sprintf("UPPER %v", context.Model))), nil
+}
+
+// TestController_ViewEngines ensures that Controller.View renders views with the engine registered
+// for their file extension
+func TestController_ViewEngines(t *testing.T) {
+	views := fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views")
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/shared", views), 0755)
+	files := map[string]string{
+		"shared/_layout.htm": "<html><head><title>{{ .ViewData.title }}</title></head><body>{{ body }}</body></html>",
+		"readme.md":          "---\ntitle: \"Read Me\"\nauthor: Dan\n---\n# Hello\n\nWritten by *{{ author }}*",
+		"notice.txt":         "Dear {{ . }}, <b>plain</b> text",
+	}
+
+	for name, data := range files {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", views, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	req, err := http.NewRequest("", "http://localhost/test/index", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := newTestController(req).ToController()
+	controller.Layout = "_layout.htm"
+
+	res := controller.View([]string{"readme.md"}, nil)
+	expected := "<html><head><title>Read Me</title></head><body><h1>Hello</h1>\n<p>Written by <em>{{ author }}</em></p></body></html>"
+	if string(res.Data) != expected {
+		t.Errorf("Unexpected markdown view: %s", res.Data)
+	}
+
+	res = controller.View([]string{"notice.txt"}, "Customer")
+	if string(res.Data) != "Dear Customer, <b>plain</b> text" || res.Headers["Content-Type"] != "text/plain; charset=utf-8" {
+		t.Errorf("Unexpected text view: %s (%s)", res.Data, res.Headers["Content-Type"])
+	}
+
+	mvcapp.ViewEngines.Register(&upperViewEngine{}, ".upper")
+	defer mvcapp.ViewEngines.Unregister(".upper")
+
+	res = controller.View([]string{"page.upper"}, "model")
+	if string(res.Data) != "UPPER model" {
+		t.Errorf("Unexpected custom engine view: %s", res.Data)
+	}
+}
+
+// TestParseFrontMatter ensures that front matter values are split from the content
+func TestParseFrontMatter(t *testing.T) {
+	values, content := mvcapp.ParseFrontMatter([]byte("---\ntitle: Hello: World\n# comment\nlayout: 'docs.htm'\n---\nBody"))
+	if values["title"] != "Hello: World" || values["layout"] != "docs.htm" || len(values) != 2 {
+		t.Errorf("Unexpected front matter values: %v", values)
+	}
+
+	if string(content) != "Body" {
+		t.Errorf("Unexpected content: %q", content)
+	}
+
+	values, content = mvcapp.ParseFrontMatter([]byte("No front matter"))
+	if len(values) != 0 || string(content) != "No front matter" {
+		t.Errorf("Unexpected result without front matter: %v %q", values, content)
+	}
+}
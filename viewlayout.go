@@ -0,0 +1,174 @@
+/*
+	Digivance MVC Application Framework
+	View Layout Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines view layouts, sections and partial views. A layout is a shared template
+	that wraps the view, rendering the view with {{ body }} and the named templates the view
+	defines with {{ section "name" }}. Partial views are rendered with {{ partial "name" model }}
+	and are found using the same folders as MakeTemplateList. Views that define the mvcapp
+	template themselves are rendered as is, without a layout.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"path/filepath"
+)
+
+// layoutFuncMap returns placeholders for the layout functions so that views can be parsed, the
+// placeholders are replaced by bindLayoutFuncs before a view is executed
+func layoutFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"body": func() (template.HTML, error) {
+			return "", errors.New("The body function can only be used in a layout")
+		},
+		"section": func(name string) (template.HTML, error) {
+			return "", nil
+		},
+		"hasSection": func(name string) bool {
+			return false
+		},
+		"partial": func(name string, data interface{}) (template.HTML, error) {
+			return "", errors.New("The partial function can only be used in a view")
+		},
+	}
+}
+
+// executeTemplateHTML renders the named template of the provided set as html
+func executeTemplateHTML(page *template.Template, name string, data interface{}) (template.HTML, error) {
+	buffer := new(bytes.Buffer)
+	if err := page.ExecuteTemplate(buffer, name, data); err != nil {
+		return "", err
+	}
+
+	return template.HTML(buffer.String()), nil
+}
+
+// bindLayoutFuncs binds the layout functions of the provided template set. The body renders the
+// view wrapped by a layout (nil when not rendering a layout), and ext is the file extension
+// tried for partials that are named without one
+func (cache *ViewCache) bindLayoutFuncs(page *template.Template, controllerName string, body func() (template.HTML, error), ext string, model interface{}, funcMap template.FuncMap) *template.Template {
+	return page.Funcs(template.FuncMap{
+		"body": func() (template.HTML, error) {
+			if body == nil {
+				return "", errors.New("The body function can only be used in a layout")
+			}
+
+			return body()
+		},
+		"section": func(name string) (template.HTML, error) {
+			if page.Lookup(name) == nil {
+				return "", nil
+			}
+
+			return executeTemplateHTML(page, name, model)
+		},
+		"hasSection": func(name string) bool {
+			return page.Lookup(name) != nil
+		},
+		"partial": func(name string, data interface{}) (template.HTML, error) {
+			return cache.renderPartial(controllerName, name, ext, data, funcMap)
+		},
+	})
+}
+
+// renderPartial resolves the named partial view (see MakeTemplateList) and renders it with the
+// provided data. Partials that are named without a file extension use ext
+func (cache *ViewCache) renderPartial(controllerName string, name string, ext string, data interface{}, funcMap template.FuncMap) (template.HTML, error) {
+	files := cache.ResolveTemplates(controllerName, []string{name})
+	if len(files) <= 0 && filepath.Ext(name) == "" && ext != "" {
+		files = cache.ResolveTemplates(controllerName, []string{name + ext})
+	}
+
+	if len(files) <= 0 {
+		return "", fmt.Errorf("Failed to find partial view: %s", name)
+	}
+
+	page, err := cache.Template(files, funcMap)
+	if err != nil {
+		return "", err
+	}
+
+	page = cache.bindLayoutFuncs(page, controllerName, nil, filepath.Ext(files[0]), data, funcMap)
+	return executeTemplateHTML(page, filepath.Base(files[0]), data)
+}
+
+// RenderView resolves the provided templates and layout (see MakeTemplateList) and renders them
+// into a new ActionResult. When the views don't define the mvcapp template and a layout is
+// provided, the layout is rendered with the first template as its body. The layout is ignored
+// for views that define the mvcapp template themselves
+func (cache *ViewCache) RenderView(controllerName string, layout string, templates []string, model interface{}, funcs template.FuncMap) (*ActionResult, error) {
+	page, entry, _, err := cache.viewTemplate(controllerName, layout, templates, model, funcs)
+	if err != nil {
+		return nil, err
+	}
+
+	return executeViewTemplate(page, entry, model)
+}
+
+// viewTemplate resolves the provided templates and layout and returns the template set, with
+// the layout functions bound, the name of the template to execute and the name of the template
+// rendered by {{ body }} (empty when the view isn't wrapped by the layout, see RenderView)
+func (cache *ViewCache) viewTemplate(controllerName string, layout string, templates []string, model interface{}, funcs template.FuncMap) (*template.Template, string, string, error) {
+	files := cache.ResolveTemplates(controllerName, templates)
+	if len(files) <= 0 {
+		return nil, "", "", fmt.Errorf("Failed to find views: %v", templates)
+	}
+
+	layoutFile := ""
+	if layout != "" {
+		layoutFiles := cache.ResolveTemplates(controllerName, []string{layout})
+		if len(layoutFiles) <= 0 {
+			return nil, "", "", fmt.Errorf("Failed to find layout: %s", layout)
+		}
+
+		layoutFile = layoutFiles[0]
+		files = append(files, layoutFile)
+	}
+
+	funcMap := viewFuncMap(funcs)
+	page, err := cache.Template(files, funcMap)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	entry, bodyName := "mvcapp", ""
+	var body func() (template.HTML, error)
+	if page.Lookup("mvcapp") == nil && layoutFile != "" {
+		entry, bodyName = filepath.Base(layoutFile), filepath.Base(files[0])
+		body = func() (template.HTML, error) {
+			return executeTemplateHTML(page, bodyName, model)
+		}
+	}
+
+	page = cache.bindLayoutFuncs(page, controllerName, body, filepath.Ext(files[0]), model, funcMap)
+	return page, entry, bodyName, nil
+}
+
+// RenderLayout resolves the provided layout (see MakeTemplateList) and renders it into a new
+// ActionResult, with the provided html content as its body. Used by view engines that produce
+// html without templates (E.g. MarkdownViewEngine)
+func (cache *ViewCache) RenderLayout(controllerName string, layout string, content template.HTML, model interface{}, funcs template.FuncMap) (*ActionResult, error) {
+	files := cache.ResolveTemplates(controllerName, []string{layout})
+	if len(files) <= 0 {
+		return nil, fmt.Errorf("Failed to find layout: %s", layout)
+	}
+
+	funcMap := viewFuncMap(funcs)
+	page, err := cache.Template(files, funcMap)
+	if err != nil {
+		return nil, err
+	}
+
+	body := func() (template.HTML, error) {
+		return content, nil
+	}
+
+	page = cache.bindLayoutFuncs(page, controllerName, body, filepath.Ext(files[0]), model, funcMap)
+	return executeViewTemplate(page, filepath.Base(files[0]), model)
+}
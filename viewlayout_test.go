@@ -0,0 +1,74 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Layout Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewlayout.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewlayout.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestViewCache_RenderView ensures that views are rendered into their layout with their sections
+// and partial views
+func TestViewCache_RenderView(t *testing.T) {
+	views := fmt.Sprintf("%s/%s", mvcapp.GetApplicationPath(), "views")
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/layouttest", views), 0755)
+	os.MkdirAll(fmt.Sprintf("%s/shared", views), 0755)
+
+	files := map[string]string{
+		"shared/_layout.htm":   "<html><body>{{ body }}{{ if hasSection \"scripts\" }}{{ section \"scripts\" }}{{ end }}{{ section \"footer\" }}</body></html>",
+		"shared/_name.htm":     "<b>{{ . }}</b>",
+		"layouttest/index.htm": "<p>Hello {{ partial \"_name\" .Name }}</p>{{ define \"scripts\" }}<script>var a = 1;</script>{{ end }}",
+		"layouttest/plain.htm": "{{ define \"mvcapp\" }}<p>No layout {{ .Name }}</p>{{ end }}",
+	}
+
+	for name, data := range files {
+		if err := ioutil.WriteFile(fmt.Sprintf("%s/%s", views, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	model := map[string]string{"Name": "World"}
+	cache := mvcapp.NewViewCache()
+
+	res, err := cache.RenderView("layouttest", "_layout.htm", []string{"index.htm"}, model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<html><body><p>Hello <b>World</b></p><script>var a = 1;</script></body></html>"
+	if string(res.Data) != expected {
+		t.Errorf("Unexpected layout result: %s", res.Data)
+	}
+
+	res, err = cache.RenderView("layouttest", "_layout.htm", []string{"plain.htm"}, model, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(res.Data) != "<p>No layout World</p>" {
+		t.Errorf("Expected views that define mvcapp to ignore the layout: %s", res.Data)
+	}
+
+	if _, err = cache.RenderView("layouttest", "_missing.htm", []string{"index.htm"}, model, nil); err == nil {
+		t.Error("Failed to report the missing layout")
+	}
+
+	files["layouttest/broken.htm"] = "{{ partial \"_missing\" . }}"
+	ioutil.WriteFile(fmt.Sprintf("%s/layouttest/broken.htm", views), []byte(files["layouttest/broken.htm"]), 0644)
+	if _, err = cache.RenderView("layouttest", "_layout.htm", []string{"broken.htm"}, model, nil); err == nil {
+		t.Error("Failed to report the missing partial view")
+	}
+}
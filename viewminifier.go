@@ -0,0 +1,177 @@
+/*
+	Digivance MVC Application Framework
+	View Minifier Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the view minifier, an opt in post processor that minifies the html, json
+	and svg output of controller results. Minification can be enabled globally from configuration
+	or per controller, and is always disabled in development mode so views remain readable.
+	Minified output is cached by the hash of the rendered output, so a view rendered again with
+	the same model isn't minified twice.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/tdewolff/minify"
+	"github.com/tdewolff/minify/css"
+	"github.com/tdewolff/minify/html"
+	"github.com/tdewolff/minify/js"
+	"github.com/tdewolff/minify/json"
+	"github.com/tdewolff/minify/svg"
+)
+
+// ViewMinifierStats are the measurements collected by a ViewMinifier
+type ViewMinifierStats struct {
+	// Count is the number of results minified
+	Count int64
+
+	// BytesIn is the total size of the results before minification
+	BytesIn int64
+
+	// BytesOut is the total size of the results after minification
+	BytesOut int64
+
+	// Duration is the total time spent minifying results
+	Duration time.Duration
+
+	// CacheHits is the number of results served from the cache without being minified again
+	CacheHits int64
+}
+
+// DefaultViewMinifierCacheSize is the default number of minified results kept by a ViewMinifier
+const DefaultViewMinifierCacheSize = 256
+
+// ViewMinifier minifies the rendered output of controller results
+type ViewMinifier struct {
+	// Minifier is the tdewolff/minify instance used, with html, json, svg and the inline
+	// css and javascript minifiers registered
+	Minifier *minify.M
+
+	// Enabled is the default for the MinifyOutput member of controllers, controllers can
+	// opt in or out individually by setting their MinifyOutput member
+	Enabled bool
+
+	// DevelopmentMode disables all minification, regardless of the controller settings
+	DevelopmentMode bool
+
+	// CacheSize is the number of minified results kept, keyed by the mime type and hash of the
+	// original output. The oldest result is removed when the cache is full, 0 disables caching
+	CacheSize int
+
+	// mutex protects the stats and cache
+	mutex sync.Mutex
+
+	// stats are the measurements of the results minified so far
+	stats ViewMinifierStats
+
+	// cache holds the minified results by key, cacheKeys holds the keys in the order added
+	cache     map[string][]byte
+	cacheKeys []string
+}
+
+// NewViewMinifier returns a new (disabled) view minifier
+func NewViewMinifier() *ViewMinifier {
+	rtn := &ViewMinifier{
+		Minifier:  minify.New(),
+		Enabled:   false,
+		CacheSize: DefaultViewMinifierCacheSize,
+		cache:     map[string][]byte{},
+	}
+
+	rtn.Minifier.AddFunc("text/html", html.Minify)
+	rtn.Minifier.AddFunc("text/css", css.Minify)
+	rtn.Minifier.AddFunc("text/javascript", js.Minify)
+	rtn.Minifier.AddFunc("image/svg+xml", svg.Minify)
+	rtn.Minifier.AddFuncRegexp(regexp.MustCompile("[/+]json$"), json.Minify)
+
+	return rtn
+}
+
+// NewViewMinifierFromConfig returns a new view minifier enabled by the MinifyViews configuration
+// value, and disabled while the application is in development mode
+func NewViewMinifierFromConfig(config *ConfigurationManager) *ViewMinifier {
+	rtn := NewViewMinifier()
+	rtn.Enabled = config.MinifyViews
+	rtn.DevelopmentMode = config.DevelopmentMode
+
+	return rtn
+}
+
+// Minify returns the provided data minified as mimeType, from the cache when the same data was
+// minified before. The original data is returned in development mode or if the data can not be
+// minified
+func (viewMinifier *ViewMinifier) Minify(mimeType string, data []byte) []byte {
+	if viewMinifier.DevelopmentMode || len(data) <= 0 {
+		return data
+	}
+
+	hash := sha256.Sum256(data)
+	key := mimeType + ":" + string(hash[:])
+
+	viewMinifier.mutex.Lock()
+	if cached, ok := viewMinifier.cache[key]; ok {
+		viewMinifier.stats.CacheHits++
+		viewMinifier.mutex.Unlock()
+		return cached
+	}
+	viewMinifier.mutex.Unlock()
+
+	start := time.Now()
+	output := new(bytes.Buffer)
+	if err := viewMinifier.Minifier.Minify(mimeType, output, bytes.NewReader(data)); err != nil {
+		LogWarningf("Failed to minify %s output: %s", mimeType, err)
+		return data
+	}
+
+	duration := time.Since(start)
+
+	viewMinifier.mutex.Lock()
+	viewMinifier.stats.Count++
+	viewMinifier.stats.BytesIn += int64(len(data))
+	viewMinifier.stats.BytesOut += int64(output.Len())
+	viewMinifier.stats.Duration += duration
+	viewMinifier.cacheResult(key, output.Bytes())
+	viewMinifier.mutex.Unlock()
+
+	LogTracef("Minified %s output from %d to %d bytes in %s", mimeType, len(data), output.Len(), duration)
+	return output.Bytes()
+}
+
+// cacheResult adds the minified result to the cache, removing the oldest results when the cache
+// is full. The mutex must be held by the caller
+func (viewMinifier *ViewMinifier) cacheResult(key string, data []byte) {
+	if viewMinifier.CacheSize <= 0 {
+		return
+	}
+
+	if viewMinifier.cache == nil {
+		viewMinifier.cache = map[string][]byte{}
+	}
+
+	if _, ok := viewMinifier.cache[key]; ok {
+		return
+	}
+
+	for len(viewMinifier.cacheKeys) >= viewMinifier.CacheSize {
+		delete(viewMinifier.cache, viewMinifier.cacheKeys[0])
+		viewMinifier.cacheKeys = viewMinifier.cacheKeys[1:]
+	}
+
+	viewMinifier.cache[key] = data
+	viewMinifier.cacheKeys = append(viewMinifier.cacheKeys, key)
+}
+
+// Stats returns the measurements of the results minified so far
+func (viewMinifier *ViewMinifier) Stats() ViewMinifierStats {
+	viewMinifier.mutex.Lock()
+	defer viewMinifier.mutex.Unlock()
+
+	return viewMinifier.stats
+}
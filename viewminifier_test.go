@@ -0,0 +1,104 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Minifier Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewminifier.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewminifier.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestViewMinifier_Minify ensures that the ViewMinifier.Minify method operates as expected
+func TestViewMinifier_Minify(t *testing.T) {
+	source := []byte("<html>\n    <body>\n        <h1>Hello</h1>\n    </body>\n</html>\n")
+
+	viewMinifier := mvcapp.NewViewMinifier()
+	result := viewMinifier.Minify("text/html", source)
+	if len(result) >= len(source) {
+		t.Errorf("Failed to minify html: %s", result)
+	}
+
+	json := viewMinifier.Minify("application/json", []byte("{ \"a\" : 1 }"))
+	if string(json) != "{\"a\":1}" {
+		t.Errorf("Failed to minify json: %s", json)
+	}
+
+	stats := viewMinifier.Stats()
+	if stats.Count != 2 || stats.BytesIn <= stats.BytesOut {
+		t.Errorf("Failed to measure minification: %+v", stats)
+	}
+
+	if cached := viewMinifier.Minify("text/html", source); string(cached) != string(result) {
+		t.Errorf("Unexpected cached minified html: %s", cached)
+	}
+
+	if stats = viewMinifier.Stats(); stats.Count != 2 || stats.CacheHits != 1 {
+		t.Errorf("Failed to serve the minified html from the cache: %+v", stats)
+	}
+
+	viewMinifier.CacheSize = 1
+	viewMinifier.Minify("application/json", []byte("{ \"b\" : 2 }"))
+	viewMinifier.Minify("application/json", []byte("{ \"a\" : 1 }"))
+	if stats = viewMinifier.Stats(); stats.Count != 4 || stats.CacheHits != 1 {
+		t.Errorf("Failed to remove the oldest cached result: %+v", stats)
+	}
+
+	config := mvcapp.NewConfigurationManager()
+	config.MinifyViews = true
+	config.DevelopmentMode = true
+
+	viewMinifier = mvcapp.NewViewMinifierFromConfig(config)
+	if !viewMinifier.Enabled || string(viewMinifier.Minify("text/html", source)) != string(source) {
+		t.Error("Failed to disable minification in development mode")
+	}
+}
+
+// TestController_MinifyResult ensures that controllers minify their results when MinifyOutput is set
+func TestController_MinifyResult(t *testing.T) {
+	config := mvcapp.NewConfigurationManager()
+	config.MinifyViews = true
+
+	manager := mvcapp.NewRouteManagerFromConfig(config)
+	manager.RegisterController("Home", newTestController)
+
+	_, controller := manager.GetController(httptest.NewRecorder(), httptest.NewRequest("GET", "/home/index", nil))
+	if controller == nil || !controller.MinifyOutput {
+		t.Fatal("Failed to enable controller minification from configuration")
+	}
+
+	result := controller.JSON(map[string]int{"a": 1})
+	if string(result.Data) != "{\"a\":1}" {
+		t.Errorf("Failed to minify json result: %s", result.Data)
+	}
+
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/test", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/test/icon.svg", views), []byte("{{ define \"mvcapp\" }}<svg>\n    <rect width=\"1\" />\n</svg>{{ end }}"), 0644)
+
+	controller.ControllerName = "test"
+	count := controller.ViewMinifier.Stats().Count
+	result = controller.SVGView([]string{"icon.svg"}, nil)
+	if !strings.HasPrefix(string(result.Data), "<svg>") || result.Headers["Content-Type"] != "image/svg+xml" || controller.ViewMinifier.Stats().Count != count+1 {
+		t.Errorf("Failed to minify the svg view once: %s (%d)", result.Data, controller.ViewMinifier.Stats().Count-count)
+	}
+
+	controller.MinifyOutput = false
+	result = controller.MinifyResult(mvcapp.NewActionResult([]byte("<p>\n    a\n</p>")), "text/html")
+	if string(result.Data) != "<p>\n    a\n</p>" {
+		t.Errorf("Failed to opt controller out of minification: %s", result.Data)
+	}
+}
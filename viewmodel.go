@@ -0,0 +1,185 @@
+/*
+	Digivance MVC Application Framework
+	View Model Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the view model envelope that Controller.View passes to every view. The
+	actions model is available as .Model, alongside the controllers .ViewData, .TempData, .User
+	and .ModelState, a safe subset of the .Request, the applications .Config, the users
+	.CSRFToken and the .Culture of the request. The envelope prints as its Model, so views that
+	simply output {{ . }} keep working.
+*/
+
+package mvcapp
+
+import (
+	"fmt"
+	"html/template"
+	"net/url"
+)
+
+// CSRFFieldName is the name of the form field that holds the CSRF token (see Controller.ValidateCSRFToken)
+const CSRFFieldName = "csrf_token"
+
+// Session keys used by the view model features
+const (
+	csrfTokenSessionKey = "mvcapp.CSRFToken"
+	tempDataSessionKey  = "mvcapp.TempData"
+)
+
+// ViewRequest is the subset of the http request that is exposed to views
+type ViewRequest struct {
+	// Method is the http method of the request (E.g. GET)
+	Method string
+
+	// Path is the url path that was requested
+	Path string
+
+	// Query is the parsed query string of the request
+	Query url.Values
+
+	// Scheme is the client facing url scheme (http or https)
+	Scheme string
+
+	// Host is the client facing host name
+	Host string
+
+	// UserAgent is the user agent header of the request
+	UserAgent string
+}
+
+// ViewConfig is the subset of the application configuration that is exposed to views
+type ViewConfig struct {
+	// AppName is the name of the application
+	AppName string
+
+	// AppVersion is the version of the application
+	AppVersion string
+
+	// DomainName is the domain name the application is served from
+	DomainName string
+
+	// DevelopmentMode is true when the application is running in development mode
+	DevelopmentMode bool
+}
+
+// NewViewConfig returns the ViewConfig of the provided configuration
+func NewViewConfig(config *ConfigurationManager) *ViewConfig {
+	return &ViewConfig{
+		AppName:         config.AppName,
+		AppVersion:      config.AppVersion,
+		DomainName:      config.DomainName,
+		DevelopmentMode: config.DevelopmentMode,
+	}
+}
+
+// ModelState is a collection of validation error messages keyed by field name
+type ModelState map[string][]string
+
+// NewModelState returns a new, valid, ModelState
+func NewModelState() ModelState {
+	return make(ModelState, 0)
+}
+
+// AddError adds a validation error message for the provided field
+func (state ModelState) AddError(field string, message string) {
+	state[field] = append(state[field], message)
+}
+
+// Errors returns the validation error messages of the provided field
+func (state ModelState) Errors(field string) []string {
+	return state[field]
+}
+
+// HasError returns true if there are validation error messages for the provided field
+func (state ModelState) HasError(field string) bool {
+	return len(state[field]) > 0
+}
+
+// IsValid returns true if there are no validation error messages
+func (state ModelState) IsValid() bool {
+	return len(state) <= 0
+}
+
+// ViewModel is the envelope passed to every view rendered by Controller.View
+type ViewModel struct {
+	// Model is the model provided by the action
+	Model interface{}
+
+	// ViewData is the controllers ViewData collection
+	ViewData map[string]interface{}
+
+	// TempData are the values stored by the previous request (see Controller.SetTempData)
+	TempData map[string]interface{}
+
+	// User is the authenticated user of the request (see Controller.User)
+	User interface{}
+
+	// ModelState are the validation errors of the request
+	ModelState ModelState
+
+	// Request is the safe subset of the http request
+	Request *ViewRequest
+
+	// Config is the safe subset of the application configuration
+	Config *ViewConfig
+
+	// CSRFToken is the users cross site request forgery token (see Controller.ValidateCSRFToken)
+	CSRFToken string
+
+	// Culture is the culture of the request (see Controller.Culture)
+	Culture string
+}
+
+// String returns the printed Model, so that views written before the envelope that print
+// the model with {{ . }} keep working
+func (viewModel *ViewModel) String() string {
+	if viewModel.Model == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", viewModel.Model)
+}
+
+// CSRFField returns the hidden form input holding the CSRFToken
+func (viewModel *ViewModel) CSRFField() template.HTML {
+	return template.HTML(fmt.Sprintf("<input type=\"hidden\" name=\"%s\" value=\"%s\" />", CSRFFieldName, template.HTMLEscapeString(viewModel.CSRFToken)))
+}
+
+// NewViewModel returns the view model envelope of the provided model for this request. The
+// model is returned as is if it already is a *ViewModel
+func (controller *Controller) NewViewModel(model interface{}) *ViewModel {
+	if viewModel, ok := model.(*ViewModel); ok {
+		return viewModel
+	}
+
+	rtn := &ViewModel{
+		Model:      model,
+		ViewData:   controller.ViewData,
+		TempData:   controller.TempData,
+		User:       controller.User,
+		ModelState: controller.ModelState,
+		Request:    &ViewRequest{},
+		Config:     controller.ViewConfig,
+		CSRFToken:  controller.CSRFToken(),
+		Culture:    controller.Culture,
+	}
+
+	if rtn.Config == nil {
+		rtn.Config = &ViewConfig{}
+	}
+
+	if controller.Request != nil {
+		forwarded := controller.Forwarded()
+		rtn.Request = &ViewRequest{
+			Method:    controller.Request.Method,
+			Path:      controller.Request.URL.Path,
+			Query:     controller.Request.URL.Query(),
+			Scheme:    forwarded.Scheme,
+			Host:      forwarded.Host,
+			UserAgent: controller.Request.UserAgent(),
+		}
+	}
+
+	return rtn
+}
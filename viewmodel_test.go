@@ -0,0 +1,112 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Model Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewmodel.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewmodel.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestController_NewViewModel ensures that views receive the view model envelope
+func TestController_NewViewModel(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/envelope", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/envelope/index.htm", views), []byte(
+		"{{ define \"mvcapp\" }}{{ .Model.Name }}|{{ .ViewData.Title }}|{{ .User }}|{{ .Request.Path }}|{{ .Request.Query.Get \"q\" }}|"+
+			"{{ .Config.AppName }}|{{ .ModelState.IsValid }}|{{ .CSRFField }}{{ end }}"), 0644)
+
+	req, err := http.NewRequest("GET", "http://localhost/envelope/index?q=search", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := newTestController(req).ToController()
+	controller.ControllerName = "envelope"
+	controller.ViewConfig = mvcapp.NewViewConfig(mvcapp.NewConfigurationManager())
+	controller.ViewData["Title"] = "Welcome"
+	controller.User = "dan"
+	controller.ModelState.AddError("Name", "Required")
+
+	res := controller.View([]string{"index.htm"}, map[string]string{"Name": "Widget"})
+	expected := fmt.Sprintf("Widget|Welcome|dan|/envelope/index|search|MyApp|false|<input type=\"hidden\" name=\"csrf_token\" value=\"%s\" />", controller.CSRFToken())
+	if string(res.Data) != expected {
+		t.Errorf("Unexpected view model output: %s", res.Data)
+	}
+
+	if model := controller.NewViewModel("Plain"); model.String() != "Plain" || controller.NewViewModel(model) != model {
+		t.Error("Unexpected view model wrapping")
+	}
+}
+
+// TestController_ValidateCSRFToken ensures that the CSRF token is validated from forms and headers
+func TestController_ValidateCSRFToken(t *testing.T) {
+	req := httptest.NewRequest("POST", "http://localhost/test/index", nil)
+	controller := newTestController(req).ToController()
+	if controller.ValidateCSRFToken() {
+		t.Error("Validated a request before a token was issued")
+	}
+
+	token := controller.CSRFToken()
+	if len(token) != 43 || strings.ContainsAny(token, "+/=") {
+		t.Errorf("Failed to encode 32 random bytes as the token: %s", token)
+	}
+
+	form := url.Values{mvcapp.CSRFFieldName: []string{token}}
+	controller.Request = httptest.NewRequest("POST", "http://localhost/test/index", strings.NewReader(form.Encode()))
+	controller.Request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if !controller.ValidateCSRFToken() {
+		t.Error("Failed to validate the form token")
+	}
+
+	controller.Request = httptest.NewRequest("POST", "http://localhost/test/index", nil)
+	controller.Request.Header.Set("X-CSRF-Token", "wrong")
+	if controller.ValidateCSRFToken() {
+		t.Error("Validated an invalid header token")
+	}
+}
+
+// TestRouteManager_TempData ensures that TempData values are available to the next request only
+func TestRouteManager_TempData(t *testing.T) {
+	manager := mvcapp.NewRouteManager()
+	manager.SessionIDKey = "TempDataSession"
+
+	req := httptest.NewRequest("GET", "http://localhost/test/index", nil)
+	first := newTestController(req).ToController()
+	if err := manager.SetControllerSessions(first); err != nil {
+		t.Fatal(err)
+	}
+
+	first.SetTempData("Message", "Saved")
+	cookie := &http.Cookie{Name: manager.SessionIDKey, Value: first.Session.ID}
+
+	for i, expected := range []interface{}{"Saved", nil} {
+		req = httptest.NewRequest("GET", "http://localhost/test/index", nil)
+		req.AddCookie(cookie)
+
+		next := newTestController(req).ToController()
+		if err := manager.SetControllerSessions(next); err != nil {
+			t.Fatal(err)
+		}
+
+		if next.TempData["Message"] != expected {
+			t.Errorf("Unexpected TempData on request %d: %v", i+1, next.TempData["Message"])
+		}
+	}
+}
@@ -0,0 +1,186 @@
+/*
+	Digivance MVC Application Framework
+	View Streaming Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines streamed views (see Controller.StreamView). Rather than rendering the whole
+	page into memory before writing it, a streamed view is rendered directly to the response.
+	The output is held back until the view calls {{ flush }} (E.g. after the closing head tag of
+	the layout), which sends the headers and the page rendered so far to the client. When the
+	view fails before its first flush, nothing has been sent and a clean error page is written
+	instead. After a flush the response can no longer be replaced and rendering errors are logged.
+*/
+
+package mvcapp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// streamFuncMap returns a placeholder for the flush function so that views can be parsed, and
+// rendered without streaming, the placeholder is replaced by StreamView
+func streamFuncMap() template.FuncMap {
+	return template.FuncMap{
+		"flush": func() (template.HTML, error) {
+			return "", nil
+		},
+	}
+}
+
+// StreamWriter writes a streamed action result to the client. The output is buffered until the
+// first Flush, which writes the status code, headers and cookies of the result
+type StreamWriter struct {
+	// response is the http response writer of the request
+	response http.ResponseWriter
+
+	// result is the action result being streamed
+	result *ActionResult
+
+	// buffer holds the output written before the first flush
+	buffer bytes.Buffer
+
+	// flushed is true once the headers have been written to the client
+	flushed bool
+}
+
+// NewStreamWriter returns a new StreamWriter of the provided result and response
+func NewStreamWriter(response http.ResponseWriter, result *ActionResult) *StreamWriter {
+	return &StreamWriter{
+		response: response,
+		result:   result,
+	}
+}
+
+// Write writes the provided data to the client, or buffers it when nothing has been flushed yet
+func (writer *StreamWriter) Write(data []byte) (int, error) {
+	if !writer.flushed {
+		return writer.buffer.Write(data)
+	}
+
+	return writer.response.Write(data)
+}
+
+// Flush writes the headers (on the first call) and the buffered output to the client and
+// flushes the response when it supports http.Flusher
+func (writer *StreamWriter) Flush() error {
+	if !writer.flushed {
+		writer.flushed = true
+		for k, v := range writer.result.Headers {
+			writer.response.Header().Set(k, v)
+		}
+
+		for _, cookie := range writer.result.Cookies {
+			http.SetCookie(writer.response, cookie)
+		}
+
+		writer.response.WriteHeader(writer.result.StatusCode)
+		if _, err := writer.response.Write(writer.buffer.Bytes()); err != nil {
+			return err
+		}
+
+		writer.buffer.Reset()
+	}
+
+	if flusher, ok := writer.response.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// Flushed returns true once the headers have been written to the client, after which the
+// response can no longer be replaced by an error page
+func (writer *StreamWriter) Flushed() bool {
+	return writer.flushed
+}
+
+// executeStream streams this action result to the client (see ActionResult.Stream)
+func (result *ActionResult) executeStream(response http.ResponseWriter) error {
+	writer := NewStreamWriter(response, result)
+	if err := result.Stream(writer); err != nil {
+		if writer.Flushed() {
+			LogError(fmt.Sprintf("Failed to stream view after the first flush: %s", err))
+			return err
+		}
+
+		LogError(fmt.Sprintf("Failed to stream view: %s", err))
+		if result.StreamErrorResult == nil {
+			return err
+		}
+
+		return result.StreamErrorResult(err).Execute(response)
+	}
+
+	return writer.Flush()
+}
+
+// StreamView resolves the provided templates and layout (see RenderView) and renders them to
+// the provided writer, binding the flush function to writer.Flush. The body of the layout is
+// rendered directly to the writer, so that views can call {{ flush }} themselves
+func (cache *ViewCache) StreamView(writer *StreamWriter, controllerName string, layout string, templates []string, model interface{}, funcs template.FuncMap) error {
+	page, entry, bodyName, err := cache.viewTemplate(controllerName, layout, templates, model, funcs)
+	if err != nil {
+		return err
+	}
+
+	page = page.Funcs(template.FuncMap{
+		"flush": func() (template.HTML, error) {
+			return "", writer.Flush()
+		},
+	})
+
+	if bodyName != "" {
+		page = page.Funcs(template.FuncMap{
+			"body": func() (template.HTML, error) {
+				return "", page.ExecuteTemplate(writer, bodyName, model)
+			},
+		})
+	}
+
+	return page.ExecuteTemplate(writer, entry, model)
+}
+
+// StreamView returns a streamed view result of the provided templates and model (see View).
+// The view is rendered to the response when the result is executed, sending the page rendered
+// so far each time the view calls {{ flush }}. Streamed views aren't minified, and views that
+// aren't rendered by the HTMLViewEngine fall back to View
+func (controller *Controller) StreamView(templates []string, model interface{}) *ActionResult {
+	if len(templates) > 0 {
+		if _, ok := ViewEngines.Engine(templates[0]).(*HTMLViewEngine); !ok {
+			return controller.View(templates, model)
+		}
+	}
+
+	controllerName := strings.ToLower(controller.ControllerName)
+	templates = controller.ViewCache.LocalizeTemplates(controllerName, controller.Culture, templates)
+	layout := controller.Layout
+	if layout != "" {
+		layout = controller.ViewCache.LocalizeTemplates(controllerName, controller.Culture, []string{layout})[0]
+	}
+
+	viewModel := controller.NewViewModel(model)
+	funcs := controller.ViewFuncs()
+	cache := controller.ViewCache
+
+	res := NewActionResult(nil)
+	res.Headers["Content-Type"] = "text/html; charset=utf-8"
+	res.Cookies = controller.Cookies
+	res.Stream = func(writer *StreamWriter) error {
+		return cache.StreamView(writer, controllerName, layout, templates, viewModel, funcs)
+	}
+
+	res.StreamErrorResult = func(err error) *ActionResult {
+		if controller.ErrorResult != nil {
+			return controller.ErrorResult(errors.New("Internal server error, failed to render page"))
+		}
+
+		return controller.DefaultErrorPage(errors.New("Internal server error, failed to render page"))
+	}
+
+	return res
+}
@@ -0,0 +1,116 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	View Stream Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of viewstream.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in viewstream.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestViewCache_StreamView ensures that streamed views send the page rendered so far on flush
+func TestViewCache_StreamView(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/streamtest", views), 0755)
+	os.MkdirAll(fmt.Sprintf("%s/shared", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/shared/_layout.htm", views), []byte("<html><head></head>{{ flush }}<body>{{ Sent }}|{{ body }}</body></html>"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/streamtest/index.htm", views), []byte("<p>{{ .Name }}</p>"), 0644)
+
+	recorder := httptest.NewRecorder()
+	result := mvcapp.NewActionResult(nil)
+	result.Headers["Content-Type"] = "text/html; charset=utf-8"
+	writer := mvcapp.NewStreamWriter(recorder, result)
+
+	funcs := template.FuncMap{
+		"Sent": func() int { return recorder.Body.Len() },
+	}
+
+	cache := mvcapp.NewViewCache()
+	if err := cache.StreamView(writer, "streamtest", "_layout.htm", []string{"index.htm"}, map[string]string{"Name": "World"}, funcs); err != nil {
+		t.Fatal(err)
+	}
+
+	if !writer.Flushed() || !recorder.Flushed {
+		t.Error("Failed to flush the streamed view")
+	}
+
+	expected := "<html><head></head><body>25|<p>World</p></body></html>"
+	if recorder.Body.String() != expected {
+		t.Errorf("Unexpected streamed view: %s", recorder.Body.String())
+	}
+
+	if recorder.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Error("Failed to write the headers on the first flush")
+	}
+
+	// The body is rendered directly to the writer, so a flush in the view sends the layout and
+	// the view rendered so far
+	ioutil.WriteFile(fmt.Sprintf("%s/shared/_plain.htm", views), []byte("<html>{{ body }}</html>"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/streamtest/flushed.htm", views), []byte("<p>{{ .Name }}</p>{{ Sent }}{{ flush }} {{ Sent }}"), 0644)
+
+	recorder = httptest.NewRecorder()
+	writer = mvcapp.NewStreamWriter(recorder, result)
+	if err := cache.StreamView(writer, "streamtest", "_plain.htm", []string{"flushed.htm"}, map[string]string{"Name": "World"}, funcs); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.Body.String() != "<html><p>World</p>0 20</html>" {
+		t.Errorf("Unexpected view flushed streamed view: %s", recorder.Body.String())
+	}
+}
+
+// TestController_StreamView ensures that streamed views render to the response, and that a clean
+// error page is written when the view fails before its first flush
+func TestController_StreamView(t *testing.T) {
+	views := fmt.Sprintf("%s/views", mvcapp.GetApplicationPath())
+	defer os.RemoveAll(views)
+
+	os.MkdirAll(fmt.Sprintf("%s/streamtest", views), 0755)
+	ioutil.WriteFile(fmt.Sprintf("%s/streamtest/index.htm", views), []byte("{{ define \"mvcapp\" }}<p>{{ .Model }}</p>{{ flush }}<p>Done</p>{{ end }}"), 0644)
+	ioutil.WriteFile(fmt.Sprintf("%s/streamtest/broken.htm", views), []byte("{{ define \"mvcapp\" }}<p>Partial output</p>{{ .Model.Missing }}{{ flush }}{{ end }}"), 0644)
+
+	req, err := http.NewRequest("GET", "http://localhost/streamtest/index", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := newTestController(req).ToController()
+	controller.ControllerName = "streamtest"
+
+	recorder := httptest.NewRecorder()
+	if err = controller.StreamView([]string{"index.htm"}, "Hello").Execute(recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	if recorder.Code != 200 || recorder.Body.String() != "<p>Hello</p><p>Done</p>" {
+		t.Errorf("Unexpected streamed view: %d %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	controller.StreamView([]string{"broken.htm"}, "Hello").Execute(recorder)
+	if !strings.Contains(recorder.Body.String(), "Error Page") || strings.Contains(recorder.Body.String(), "Partial output") {
+		t.Errorf("Failed to write a clean error page: %s", recorder.Body.String())
+	}
+
+	// Views that aren't streamed ignore the flush function
+	res := controller.View([]string{"index.htm"}, "Hello")
+	if string(res.Data) != "<p>Hello</p><p>Done</p>" {
+		t.Errorf("Unexpected buffered view: %s", res.Data)
+	}
+}
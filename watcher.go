@@ -0,0 +1,259 @@
+/*
+	Digivance MVC Application Framework
+	File Watcher Features
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the file watcher. The watcher polls groups of files (such as the content bundle
+	sources, the views and the configuration files) for changes and emits debounced events that other
+	systems subscribe to. Polling is used rather than operating system notifications so that the
+	watcher behaves the same on every platform, network share and container volume.
+*/
+
+package mvcapp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Watch event kinds used by the application watcher
+const (
+	// WatchKindBundle is emitted when the source files of a content bundle change
+	WatchKindBundle = "bundle"
+
+	// WatchKindView is emitted when view templates change
+	WatchKindView = "view"
+
+	// WatchKindConfig is emitted when the configuration or bundle manifest files change
+	WatchKindConfig = "config"
+
+	// WatchKindLocale is emitted when the message catalogs change
+	WatchKindLocale = "locale"
+)
+
+// WatchEvent describes a set of files of one kind that were added, modified or removed
+type WatchEvent struct {
+	// Kind is the name of the watched group of files (E.g. WatchKindBundle)
+	Kind string
+
+	// Files is the full path and filename of each changed file
+	Files []string
+
+	// Time is when the event was emitted
+	Time time.Time
+}
+
+// WatchHandler is a callback that receives the events emitted by a Watcher
+type WatchHandler func(event *WatchEvent)
+
+// WatchSource returns the full path and filenames of a watched group of files, it is called
+// on every poll so that new files are picked up
+type WatchSource func() []string
+
+// Watcher polls groups of files for changes and emits debounced WatchEvents to its subscribers
+type Watcher struct {
+	// Interval is the time between polls of the file system
+	Interval time.Duration
+
+	// Debounce is the time the files must remain unchanged before an event is emitted, so
+	// that a burst of saves results in a single event
+	Debounce time.Duration
+
+	// mutex protects the members below across the polling goroutine
+	mutex sync.Mutex
+
+	// sources are the watched groups of files by kind
+	sources map[string]WatchSource
+
+	// handlers are the subscribers to the emitted events
+	handlers []WatchHandler
+
+	// modTimes are the last seen modification times of the watched files by kind
+	modTimes map[string]map[string]time.Time
+
+	// pending are the changed files awaiting the debounce period by kind
+	pending map[string]map[string]bool
+
+	// lastChange is the time the most recent change was seen
+	lastChange time.Time
+
+	// stop is closed to stop the polling goroutine
+	stop chan struct{}
+}
+
+// NewWatcher returns a new Watcher with the provided poll interval and debounce duration
+func NewWatcher(interval time.Duration, debounce time.Duration) *Watcher {
+	return &Watcher{
+		Interval: interval,
+		Debounce: debounce,
+		sources:  make(map[string]WatchSource, 0),
+		handlers: []WatchHandler{},
+		modTimes: make(map[string]map[string]time.Time, 0),
+		pending:  make(map[string]map[string]bool, 0),
+	}
+}
+
+// NewWatcherFromConfig returns a new Watcher using the WatchInterval and WatchDebounce values
+// (in milliseconds) of the provided configuration
+func NewWatcherFromConfig(config *ConfigurationManager) *Watcher {
+	return NewWatcher(time.Duration(config.WatchInterval)*time.Millisecond, time.Duration(config.WatchDebounce)*time.Millisecond)
+}
+
+// WatchFunc watches the files returned by the provided source as the named kind
+func (watcher *Watcher) WatchFunc(kind string, source WatchSource) {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	watcher.sources[kind] = source
+	watcher.modTimes[kind] = watcher.scan(source)
+}
+
+// Watch watches the files matching the provided filenames, directories and glob patterns (see
+// BundleMap.Files for the supported patterns) as the named kind
+func (watcher *Watcher) Watch(kind string, patterns ...string) {
+	bundleMap := NewBundleMap("", patterns)
+	watcher.WatchFunc(kind, func() []string {
+		files, err := bundleMap.ResolveFiles()
+		if err != nil {
+			LogWarningf("Failed to resolve watched %s files: %s", kind, err)
+		}
+
+		return files
+	})
+}
+
+// Subscribe adds a handler that is called for every event emitted by this watcher
+func (watcher *Watcher) Subscribe(handler WatchHandler) {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	watcher.handlers = append(watcher.handlers, handler)
+}
+
+// scan returns the modification times of the files returned by the provided source
+func (watcher *Watcher) scan(source WatchSource) map[string]time.Time {
+	rtn := make(map[string]time.Time, 0)
+	for _, filename := range source() {
+		if si, err := statFile(filename); err == nil {
+			rtn[filename] = si.ModTime()
+		}
+	}
+
+	return rtn
+}
+
+// Poll scans the watched files once and returns the events that are ready to be emitted (those
+// whose files have not changed for the Debounce duration). Start calls this every Interval
+func (watcher *Watcher) Poll() []*WatchEvent {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	now := time.Now()
+	for kind, source := range watcher.sources {
+		previous := watcher.modTimes[kind]
+		current := watcher.scan(source)
+
+		changed := []string{}
+		for filename, modTime := range current {
+			if lastModTime, ok := previous[filename]; !ok || !modTime.Equal(lastModTime) {
+				changed = append(changed, filename)
+			}
+		}
+
+		for filename := range previous {
+			if _, ok := current[filename]; !ok {
+				changed = append(changed, filename)
+			}
+		}
+
+		watcher.modTimes[kind] = current
+		if len(changed) <= 0 {
+			continue
+		}
+
+		if watcher.pending[kind] == nil {
+			watcher.pending[kind] = make(map[string]bool, 0)
+		}
+
+		for _, filename := range changed {
+			watcher.pending[kind][filename] = true
+		}
+
+		watcher.lastChange = now
+	}
+
+	if len(watcher.pending) <= 0 || now.Sub(watcher.lastChange) < watcher.Debounce {
+		return []*WatchEvent{}
+	}
+
+	rtn := []*WatchEvent{}
+	for kind, files := range watcher.pending {
+		event := &WatchEvent{Kind: kind, Files: []string{}, Time: now}
+		for filename := range files {
+			event.Files = append(event.Files, filename)
+		}
+
+		sort.Strings(event.Files)
+		rtn = append(rtn, event)
+	}
+
+	sort.Slice(rtn, func(i int, j int) bool { return rtn[i].Kind < rtn[j].Kind })
+	watcher.pending = make(map[string]map[string]bool, 0)
+	return rtn
+}
+
+// Emit logs the provided event and passes it to each of the subscribed handlers
+func (watcher *Watcher) Emit(event *WatchEvent) {
+	watcher.mutex.Lock()
+	handlers := append([]WatchHandler{}, watcher.handlers...)
+	watcher.mutex.Unlock()
+
+	LogMessagef("Watcher detected %d changed %s file(s): %v", len(event.Files), event.Kind, event.Files)
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Start starts a goroutine that polls the watched files every Interval and emits the events,
+// until Stop is called
+func (watcher *Watcher) Start() {
+	watcher.Stop()
+
+	interval := watcher.Interval
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	stop := make(chan struct{})
+	watcher.mutex.Lock()
+	watcher.stop = stop
+	watcher.mutex.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				for _, event := range watcher.Poll() {
+					watcher.Emit(event)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the goroutine started by Start
+func (watcher *Watcher) Stop() {
+	watcher.mutex.Lock()
+	defer watcher.mutex.Unlock()
+
+	if watcher.stop != nil {
+		close(watcher.stop)
+		watcher.stop = nil
+	}
+}
@@ -0,0 +1,106 @@
+/*
+	Digivance MVC Application Framework - Unit Tests
+	File Watcher Tests
+	Dan Mayor (dmayor@digivance.com)
+
+	This file defines the version 0.3.0 compatibility of watcher.go functions. These functions are written
+	to demonstrate and test the intended use cases of the functions in watcher.go
+*/
+
+package mvcapp_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/digivance/mvcapp"
+)
+
+// TestWatcher_Poll ensures that the Watcher reports added, modified and removed files once
+// the debounce period has passed
+func TestWatcher_Poll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mvcapp-watcher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	first := fmt.Sprintf("%s/first.js", dir)
+	second := fmt.Sprintf("%s/second.js", dir)
+	ioutil.WriteFile(first, []byte("var a = 1;"), 0644)
+
+	watcher := mvcapp.NewWatcher(time.Millisecond, 0)
+	watcher.WatchFunc(mvcapp.WatchKindBundle, func() []string {
+		return []string{first, second}
+	})
+
+	if events := watcher.Poll(); len(events) != 0 {
+		t.Fatalf("Expected no events before changes, got %d", len(events))
+	}
+
+	ioutil.WriteFile(second, []byte("var b = 2;"), 0644)
+	os.Chtimes(first, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+
+	events := watcher.Poll()
+	if len(events) != 1 || events[0].Kind != mvcapp.WatchKindBundle || len(events[0].Files) != 2 {
+		t.Fatalf("Expected one bundle event with two files, got %v", events)
+	}
+
+	os.Remove(second)
+	events = watcher.Poll()
+	if len(events) != 1 || len(events[0].Files) != 1 || events[0].Files[0] != second {
+		t.Fatalf("Expected one event for the removed file, got %v", events)
+	}
+
+	if events := watcher.Poll(); len(events) != 0 {
+		t.Fatalf("Expected no events once changes were reported, got %d", len(events))
+	}
+}
+
+// TestWatcher_Debounce ensures that a burst of changes results in a single event once the files
+// have remained unchanged for the Debounce duration
+func TestWatcher_Debounce(t *testing.T) {
+	dir := fmt.Sprintf("%s/watchtest", mvcapp.GetApplicationPath())
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := fmt.Sprintf("%s/site.css", dir)
+	ioutil.WriteFile(filename, []byte("body{}"), 0644)
+
+	watcher := mvcapp.NewWatcher(10*time.Millisecond, 50*time.Millisecond)
+	watcher.Watch(mvcapp.WatchKindView, "watchtest/**")
+
+	received := make(chan *mvcapp.WatchEvent, 10)
+	watcher.Subscribe(func(event *mvcapp.WatchEvent) {
+		received <- event
+	})
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	for i := 1; i <= 3; i++ {
+		modTime := time.Now().Add(time.Duration(i) * time.Minute)
+		os.Chtimes(filename, modTime, modTime)
+		time.Sleep(15 * time.Millisecond)
+	}
+
+	select {
+	case event := <-received:
+		if event.Kind != mvcapp.WatchKindView || len(event.Files) != 1 || event.Files[0] != filename {
+			t.Fatalf("Unexpected watch event: %v", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Failed to receive the watch event")
+	}
+
+	select {
+	case event := <-received:
+		t.Fatalf("Expected a single debounced event, got another: %v", event)
+	case <-time.After(150 * time.Millisecond):
+	}
+}